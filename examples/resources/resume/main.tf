@@ -7,15 +7,39 @@ terraform {
 }
 
 provider "resume" {
-  endpoint = "http://localhost:3000"
-  token    = "test"
+  endpoint       = "http://localhost:3000"
+  token          = "test"
+  default_region = "US"
 }
 
 resource "resume_resume" "this" {
-  name         = "Michael G Scott"
-  address      = "1 Paper St"
+  name    = "Michael G Scott"
+  address = {
+    street_lines = ["1 Paper St"]
+    city         = "Scranton"
+    region       = "PA"
+    postal_code  = "18505"
+    country_code = "US"
+  }
   phone_number = "555-555-5555"
   website      = "https://michaelthesco.tt"
+  emails = [
+    {
+      address = "michael@michaelthesco.tt"
+      primary = true
+    },
+    {
+      address = "michael.scott@dundermifflin.com"
+      label   = "work"
+    },
+  ]
+  profiles = [
+    {
+      network  = "LinkedIn"
+      username = "michaelgscott"
+      url      = "https://www.linkedin.com/in/michaelgscott"
+    },
+  ]
 }
 
 output "id" {
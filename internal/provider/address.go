@@ -0,0 +1,260 @@
+package provider
+
+import (
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/lagerfeuer/terraform-provider-resume/internal/resumeapi"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var _ validator.String = countryCodeValidator{}
+
+// resumeAddressModel is the address of a resume, nil if it is null.
+type resumeAddressModel struct {
+	StreetLines []types.String `tfsdk:"street_lines"`
+	City        types.String   `tfsdk:"city"`
+	Region      types.String   `tfsdk:"region"`
+	PostalCode  types.String   `tfsdk:"postal_code"`
+	CountryCode types.String   `tfsdk:"country_code"`
+}
+
+// resumeAddressAttributes are the attributes of the address of resume_resume.
+func resumeAddressAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"street_lines": schema.ListAttribute{
+			Description: "Street address, one line per element",
+			ElementType: types.StringType,
+			Optional:    true,
+		},
+		"city": schema.StringAttribute{
+			Optional: true,
+		},
+		"region": schema.StringAttribute{
+			Description: "State, province or county",
+			Optional:    true,
+		},
+		"postal_code": schema.StringAttribute{
+			Optional: true,
+		},
+		"country_code": schema.StringAttribute{
+			Description: "ISO 3166-1 alpha-2 code of the country in upper case, e.g. \"US\"",
+			Optional:    true,
+			Validators: []validator.String{
+				countryCodeValidator{},
+			},
+		},
+	}
+}
+
+func (m *resumeAddressModel) toAPI() *resumeapi.Address {
+	if m == nil {
+		return nil
+	}
+
+	address := &resumeapi.Address{
+		City:        m.City.ValueStringPointer(),
+		Region:      m.Region.ValueStringPointer(),
+		PostalCode:  m.PostalCode.ValueStringPointer(),
+		CountryCode: m.CountryCode.ValueStringPointer(),
+	}
+	if m.StreetLines != nil {
+		address.StreetLines = make([]string, 0, len(m.StreetLines))
+		for _, line := range m.StreetLines {
+			address.StreetLines = append(address.StreetLines, line.ValueString())
+		}
+	}
+	return address
+}
+
+func addressFromAPI(address *resumeapi.Address) *resumeAddressModel {
+	if address == nil {
+		return nil
+	}
+
+	m := &resumeAddressModel{
+		City:        types.StringPointerValue(address.City),
+		Region:      types.StringPointerValue(address.Region),
+		PostalCode:  types.StringPointerValue(address.PostalCode),
+		CountryCode: types.StringPointerValue(address.CountryCode),
+	}
+	if address.StreetLines != nil {
+		m.StreetLines = make([]types.String, 0, len(address.StreetLines))
+		for _, line := range address.StreetLines {
+			m.StreetLines = append(m.StreetLines, types.StringValue(line))
+		}
+	}
+	return m
+}
+
+// iso3166CSV lists the ISO 3166-1 countries with their alpha-2 code, alpha-3
+// code and English short name.
+//
+//go:embed data/iso3166-1.csv
+var iso3166CSV string
+
+type country struct {
+	alpha2, alpha3, name string
+}
+
+var (
+	loadCountriesOnce sync.Once
+	// countries are keyed by their alpha-2 code.
+	countries map[string]country
+)
+
+func loadCountries() map[string]country {
+	loadCountriesOnce.Do(func() {
+		records, err := csv.NewReader(strings.NewReader(iso3166CSV)).ReadAll()
+		if err != nil {
+			panic(fmt.Sprintf("invalid ISO 3166-1 dataset: %v", err))
+		}
+
+		countries = make(map[string]country, len(records)-1)
+		for _, record := range records[1:] {
+			countries[record[0]] = country{alpha2: record[0], alpha3: record[1], name: record[2]}
+		}
+	})
+	return countries
+}
+
+// lookupCountry returns the alpha-2 code of the country with the alpha-2
+// code, alpha-3 code or English name value, ignoring case.
+func lookupCountry(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if c, ok := loadCountries()[strings.ToUpper(value)]; ok {
+		return c.alpha2, true
+	}
+	for _, c := range loadCountries() {
+		if strings.EqualFold(value, c.alpha3) || strings.EqualFold(value, c.name) {
+			return c.alpha2, true
+		}
+	}
+	return "", false
+}
+
+// countryCodeValidator validates upper case ISO 3166-1 alpha-2 codes.
+type countryCodeValidator struct{}
+
+func (v countryCodeValidator) Description(ctx context.Context) string {
+	return "value must be an upper case ISO 3166-1 alpha-2 country code"
+}
+
+func (v countryCodeValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v countryCodeValidator) ValidateString(
+	ctx context.Context, req validator.StringRequest, resp *validator.StringResponse,
+) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if _, ok := loadCountries()[value]; ok {
+		return
+	}
+
+	detail := fmt.Sprintf("%q is not an ISO 3166-1 alpha-2 country code.", value)
+	if code, ok := lookupCountry(value); ok {
+		detail += fmt.Sprintf(" Did you mean %q?", code)
+	}
+	resp.Diagnostics.AddAttributeError(req.Path, "Invalid Country Code", detail)
+}
+
+var (
+	// addressRegionPattern matches abbreviated regions like the states of
+	// the US.
+	addressRegionPattern = regexp.MustCompile(`^[A-Z]{2,3}$`)
+	addressDigitPattern  = regexp.MustCompile(`[0-9]`)
+)
+
+// parseAddress makes a best effort to split a free-text address into its
+// parts. The lines, or the comma separated parts of a single line, are taken
+// to end with the country and a part holding the postal code, which is
+// preceded or followed by the city, or followed by a region after a part
+// with the city. Whatever is not recognized ends up in the street lines.
+func parseAddress(text string) *resumeAddressModel {
+	var parts []string
+	separator := "\n"
+	if !strings.Contains(text, "\n") {
+		separator = ","
+	}
+	for _, part := range strings.Split(text, separator) {
+		if part = strings.Trim(strings.TrimSpace(part), ","); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+
+	address := &resumeAddressModel{
+		City:        types.StringNull(),
+		Region:      types.StringNull(),
+		PostalCode:  types.StringNull(),
+		CountryCode: types.StringNull(),
+	}
+
+	if len(parts) > 1 {
+		if code, ok := lookupCountry(parts[len(parts)-1]); ok {
+			address.CountryCode = types.StringValue(code)
+			parts = parts[:len(parts)-1]
+		}
+	}
+
+	if len(parts) > 1 {
+		last := parts[len(parts)-1]
+		if postalCode, rest, ok := splitPostalCode(last); ok {
+			address.PostalCode = types.StringValue(postalCode)
+			parts = parts[:len(parts)-1]
+			switch {
+			case addressRegionPattern.MatchString(rest) && len(parts) > 1:
+				address.Region = types.StringValue(rest)
+				address.City = types.StringValue(parts[len(parts)-1])
+				parts = parts[:len(parts)-1]
+			case rest != "":
+				address.City = types.StringValue(rest)
+			}
+		}
+	}
+
+	for _, part := range parts {
+		address.StreetLines = append(address.StreetLines, types.StringValue(part))
+	}
+	return address
+}
+
+// splitPostalCode splits the words of part containing digits at its start or
+// end off as the postal code.
+func splitPostalCode(part string) (postalCode, rest string, ok bool) {
+	words := strings.Fields(part)
+
+	end := len(words)
+	for end > 0 && addressDigitPattern.MatchString(words[end-1]) {
+		end--
+	}
+	if end < len(words) && end > 0 {
+		return strings.Join(words[end:], " "), strings.Join(words[:end], " "), true
+	}
+
+	start := 0
+	for start < len(words) && addressDigitPattern.MatchString(words[start]) {
+		start++
+	}
+	if start > 0 && start < len(words) {
+		return strings.Join(words[:start], " "), strings.Join(words[start:], " "), true
+	}
+
+	if end == 0 && len(words) > 0 {
+		return part, "", true
+	}
+	return "", "", false
+}
@@ -0,0 +1,209 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testAddress returns an address with street lines, city, region, postal
+// code and country code in fields, empty ones are null.
+func testAddress(streetLines []string, fields ...string) *resumeAddressModel {
+	values := make([]types.String, 4)
+	for i := range values {
+		values[i] = types.StringNull()
+		if i < len(fields) && fields[i] != "" {
+			values[i] = types.StringValue(fields[i])
+		}
+	}
+
+	address := &resumeAddressModel{City: values[0], Region: values[1], PostalCode: values[2], CountryCode: values[3]}
+	for _, line := range streetLines {
+		address.StreetLines = append(address.StreetLines, types.StringValue(line))
+	}
+	return address
+}
+
+func TestParseAddress(t *testing.T) {
+	cases := map[string]*resumeAddressModel{
+		"":    nil,
+		" , ": nil,
+		"1725 Slough Avenue, Scranton, PA 18505, USA": testAddress(
+			[]string{"1725 Slough Avenue"}, "Scranton", "PA", "18505", "US",
+		),
+		"1725 Slough Avenue\nSuite 200\nScranton, PA 18505\nUnited States": testAddress(
+			[]string{"1725 Slough Avenue", "Suite 200"}, "Scranton, PA", "", "18505", "US",
+		),
+		"10 Downing Street, London SW1A 2AA, United Kingdom": testAddress(
+			[]string{"10 Downing Street"}, "London", "", "SW1A 2AA", "GB",
+		),
+		"Platz der Republik 1, 11011 Berlin, DE": testAddress(
+			[]string{"Platz der Republik 1"}, "Berlin", "", "11011", "DE",
+		),
+		"1725 Slough Avenue, Scranton": testAddress([]string{"1725 Slough Avenue", "Scranton"}),
+		"Scranton Business Park":       testAddress([]string{"Scranton Business Park"}),
+		"Canada":                       testAddress([]string{"Canada"}),
+	}
+
+	for text, expected := range cases {
+		if address := parseAddress(text); !reflect.DeepEqual(address, expected) {
+			t.Errorf("expected %q to be parsed as %+v, got %+v", text, expected, address)
+		}
+	}
+}
+
+func TestCountryCodeValidator(t *testing.T) {
+	cases := map[string]string{
+		"US":  "",
+		"GB":  "",
+		"AQ":  "",
+		"us":  `Did you mean "US"?`,
+		"USA": `Did you mean "US"?`,
+		"UK":  "is not an ISO 3166-1 alpha-2 country code.",
+		"XX":  "is not an ISO 3166-1 alpha-2 country code.",
+		"":    "is not an ISO 3166-1 alpha-2 country code.",
+	}
+
+	for value, expected := range cases {
+		req := validator.StringRequest{Path: path.Root("country_code"), ConfigValue: types.StringValue(value)}
+		var resp validator.StringResponse
+		countryCodeValidator{}.ValidateString(context.Background(), req, &resp)
+
+		if expected == "" {
+			if resp.Diagnostics.HasError() {
+				t.Errorf("expected %q to be valid, got %v", value, resp.Diagnostics)
+			}
+			continue
+		}
+		if errs := resp.Diagnostics.Errors(); len(errs) != 1 || !strings.HasSuffix(errs[0].Detail(), expected) {
+			t.Errorf("expected %q to be rejected with %q, got %v", value, expected, resp.Diagnostics)
+		}
+	}
+
+	if countries := loadCountries(); len(countries) != 249 {
+		t.Errorf("expected the 249 countries of ISO 3166-1, got %d", len(countries))
+	}
+}
+
+func TestResumeResourceAddress(t *testing.T) {
+	api := newFakeAPI(t)
+	p := api.provider(api.config())
+
+	plan := testResumePlan("Michael G Scott")
+	plan.Address = testAddress([]string{"1725 Slough Avenue", ""}, "Scranton", "PA", "18505", "US")
+	created, private, diags := p.applyResume(nil, &plan, nil)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if !reflect.DeepEqual(created.Address, plan.Address) {
+		t.Errorf("expected created address %+v, got %+v", plan.Address, created.Address)
+	}
+
+	expected := map[string]interface{}{
+		"street_lines": []interface{}{"1725 Slough Avenue", ""},
+		"city":         "Scranton",
+		"region":       "PA",
+		"postal_code":  "18505",
+		"country_code": "US",
+	}
+	if stored, _ := api.field(1, "address"); !reflect.DeepEqual(stored, expected) {
+		t.Errorf("expected address to be stored as %v, got %v", expected, stored)
+	}
+
+	planned := created
+	planned.Address = testAddress(nil, "Stamford", "", "", "US")
+	updated, private, diags := p.applyResume(&created, &planned, private)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if !reflect.DeepEqual(updated.Address, planned.Address) {
+		t.Errorf("expected updated address %+v, got %+v", planned.Address, updated.Address)
+	}
+	expected = map[string]interface{}{"street_lines": nil, "city": "Stamford", "country_code": "US"}
+	if stored, _ := api.field(1, "address"); !reflect.DeepEqual(stored, expected) {
+		t.Errorf("expected address to be replaced by %v, got %v", expected, stored)
+	}
+
+	planned = updated
+	planned.Address = nil
+	cleared, _, diags := p.applyResume(&updated, &planned, private)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if stored, ok := api.field(1, "address"); cleared.Address != nil || !ok || stored != nil {
+		t.Errorf("expected address to be cleared, got %+v stored as %v", cleared.Address, stored)
+	}
+}
+
+func TestResumeResourceUpgradeState(t *testing.T) {
+	api := newFakeAPI(t)
+	p := api.provider(api.config())
+
+	state, diags := p.upgradeResume(0, `{
+		"id": "1",
+		"name": "Michael G Scott",
+		"address": "1725 Slough Avenue, Scranton, PA 18505, USA",
+		"phone_number": "+1 555 555 5555",
+		"website": "https://michaelthesco.tt"
+	}`)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	expected := resumeResourceModel{
+		Id:              types.StringValue("1"),
+		Name:            types.StringValue("Michael G Scott"),
+		Address:         testAddress([]string{"1725 Slough Avenue"}, "Scranton", "PA", "18505", "US"),
+		PhoneNumber:     NewPhoneNumberValue("+1 555 555 5555"),
+		Website:         NewURLValue("https://michaelthesco.tt"),
+		PhoneNumberE164: types.StringValue("+15555555555"),
+		Timeouts:        testResumeTimeouts(nil),
+	}
+	if !reflect.DeepEqual(state, expected) {
+		t.Errorf("expected upgraded state %+v, got %+v", expected, state)
+	}
+
+	state, diags = p.upgradeResume(0, `{"id": "1", "name": "Michael G Scott", "address": null}`)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if state.Address != nil || !state.PhoneNumberE164.IsNull() || !state.Website.IsNull() {
+		t.Errorf("expected null attributes to stay null, got %+v", state)
+	}
+}
+
+func TestResumeResourceUpgradeStateRefresh(t *testing.T) {
+	api := newFakeAPI(t)
+	p := api.provider(api.config())
+
+	plan := testResumePlan("Michael G Scott")
+	plan.Address = testAddress([]string{"1725 Slough Avenue", "Suite 200"}, "Scranton", "PA", "18505", "US")
+	created, _, diags := p.applyResume(nil, &plan, nil)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	upgraded, diags := p.upgradeResume(0, `{
+		"id": "1",
+		"name": "Michael G Scott",
+		"address": "1725 Slough Avenue Suite 200 Scranton PA 18505"
+	}`)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	refreshed, _, diags := p.readResume(upgraded, nil)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if !reflect.DeepEqual(refreshed.Address, created.Address) {
+		t.Errorf("expected the address %+v stored by the API, got %+v", created.Address, refreshed.Address)
+	}
+	if ifNoneMatch := api.Header().Get("If-None-Match"); ifNoneMatch != "" {
+		t.Errorf("expected the resume to be read unconditionally, got If-None-Match %s", ifNoneMatch)
+	}
+}
@@ -0,0 +1,300 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenExpiryDelta is how long before its expiry a token is refreshed.
+const tokenExpiryDelta = 30 * time.Second
+
+// authModel describes the auth block of the provider.
+type authModel struct {
+	ClientCredentials *clientCredentialsModel `tfsdk:"client_credentials"`
+	TokenFile         types.String            `tfsdk:"token_file"`
+	TokenCommand      types.List              `tfsdk:"token_command"`
+}
+
+type clientCredentialsModel struct {
+	TokenURL     types.String `tfsdk:"token_url"`
+	ClientID     types.String `tfsdk:"client_id"`
+	ClientSecret types.String `tfsdk:"client_secret"`
+	Scopes       types.List   `tfsdk:"scopes"`
+}
+
+// tokenSource returns the token source configured in the auth block. Tokens
+// are fetched with httpClient.
+func (m *authModel) tokenSource(ctx context.Context, httpClient *http.Client) (tokenSource, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	configured := 0
+	for _, set := range []bool{m.ClientCredentials != nil, !m.TokenFile.IsNull(), !m.TokenCommand.IsNull()} {
+		if set {
+			configured++
+		}
+	}
+	if configured != 1 {
+		diags.AddAttributeError(
+			path.Root("auth"),
+			"Invalid Auth configuration",
+			"Exactly one of client_credentials, token_file and token_command must be set.",
+		)
+		return nil, diags
+	}
+
+	switch {
+	case m.ClientCredentials != nil:
+		cc := m.ClientCredentials
+		attrs := map[string]types.String{
+			"token_url":     cc.TokenURL,
+			"client_id":     cc.ClientID,
+			"client_secret": cc.ClientSecret,
+		}
+		for _, name := range []string{"token_url", "client_id", "client_secret"} {
+			if attrs[name].ValueString() == "" {
+				diags.AddAttributeError(
+					path.Root("auth").AtName("client_credentials").AtName(name),
+					"Missing OAuth2 configuration",
+					fmt.Sprintf("%s is required for the client credentials grant.", name),
+				)
+			}
+		}
+
+		var scopes []string
+		diags.Append(cc.Scopes.ElementsAs(ctx, &scopes, false)...)
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		return &clientCredentials{
+			tokenURL:     cc.TokenURL.ValueString(),
+			clientID:     cc.ClientID.ValueString(),
+			clientSecret: cc.ClientSecret.ValueString(),
+			scopes:       scopes,
+			httpClient:   httpClient,
+			now:          time.Now,
+		}, diags
+	case !m.TokenFile.IsNull():
+		return &tokenFile{path: m.TokenFile.ValueString()}, diags
+	default:
+		var args []string
+		diags.Append(m.TokenCommand.ElementsAs(ctx, &args, false)...)
+		if diags.HasError() {
+			return nil, diags
+		}
+		if len(args) == 0 {
+			diags.AddAttributeError(
+				path.Root("auth").AtName("token_command"),
+				"Invalid Token Command",
+				"The token command must not be empty.",
+			)
+			return nil, diags
+		}
+
+		return &tokenCommand{args: args, now: time.Now}, diags
+	}
+}
+
+// tokenSource provides the bearer token for API requests.
+type tokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+type staticToken string
+
+func (t staticToken) Token(context.Context) (string, error) {
+	return string(t), nil
+}
+
+// cachedToken is a token which is valid until expiry, or forever if expiry
+// is zero.
+type cachedToken struct {
+	token  string
+	expiry time.Time
+}
+
+func (t cachedToken) valid(now time.Time) bool {
+	return t.token != "" && (t.expiry.IsZero() || now.Add(tokenExpiryDelta).Before(t.expiry))
+}
+
+// clientCredentials fetches tokens with the OAuth2 client credentials grant
+// as defined by RFC 6749 section 4.4.
+type clientCredentials struct {
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scopes       []string
+	httpClient   *http.Client
+	now          func() time.Time
+
+	mu     sync.Mutex
+	cached cachedToken
+}
+
+func (c *clientCredentials) Token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cached.valid(c.now()) {
+		return c.cached.token, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(c.scopes) > 0 {
+		form.Set("scope", strings.Join(c.scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(c.clientID), url.QueryEscape(c.clientSecret))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("could not fetch OAuth2 token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("could not read OAuth2 token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("could not fetch OAuth2 token: %s returned %d: %s", c.tokenURL, resp.StatusCode, body)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", fmt.Errorf("could not parse OAuth2 token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("OAuth2 token response of %s contains no access_token", c.tokenURL)
+	}
+
+	c.cached = cachedToken{token: token.AccessToken}
+	if token.ExpiresIn > 0 {
+		c.cached.expiry = c.now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+
+	return c.cached.token, nil
+}
+
+// tokenFile reads the token from a file, which is read again whenever it
+// changes. This works with tokens rotated by an agent or a Kubernetes
+// projected volume.
+type tokenFile struct {
+	path string
+
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+	size    int64
+}
+
+func (f *tokenFile) Token(context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", fmt.Errorf("could not read token file: %w", err)
+	}
+	if f.token != "" && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.token, nil
+	}
+
+	content, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", fmt.Errorf("could not read token file: %w", err)
+	}
+
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", f.path)
+	}
+
+	f.token, f.modTime, f.size = token, info.ModTime(), info.Size()
+	return f.token, nil
+}
+
+// tokenCommand runs a helper command and uses its output as token. The
+// output is either the token itself, which is used for the rest of the run,
+// or a JSON object like
+//
+//	{"token": "...", "expires_at": "2023-07-01T12:00:00Z"}
+//
+// in which case the command is run again shortly before the token expires.
+type tokenCommand struct {
+	args []string
+	now  func() time.Time
+
+	mu     sync.Mutex
+	cached cachedToken
+}
+
+func (c *tokenCommand) Token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cached.valid(c.now()) {
+		return c.cached.token, nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.args[0], c.args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("token command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	cached, err := parseTokenCommandOutput(stdout.Bytes())
+	if err != nil {
+		return "", err
+	}
+
+	c.cached = cached
+	return c.cached.token, nil
+}
+
+func parseTokenCommandOutput(output []byte) (cachedToken, error) {
+	output = bytes.TrimSpace(output)
+
+	if bytes.HasPrefix(output, []byte("{")) {
+		var token struct {
+			Token     string    `json:"token"`
+			ExpiresAt time.Time `json:"expires_at"`
+		}
+		if err := json.Unmarshal(output, &token); err != nil {
+			return cachedToken{}, fmt.Errorf("could not parse token command output: %w", err)
+		}
+		if token.Token == "" {
+			return cachedToken{}, fmt.Errorf("token command output contains no token")
+		}
+		return cachedToken{token: token.Token, expiry: token.ExpiresAt}, nil
+	}
+
+	if len(output) == 0 {
+		return cachedToken{}, fmt.Errorf("token command returned no token")
+	}
+	return cachedToken{token: string(output)}, nil
+}
@@ -0,0 +1,253 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+// newTokenEndpoint is a stand-in for an OAuth2 token endpoint. It issues the
+// tokens token-1, token-2, ... which expire after expiresIn seconds.
+func newTokenEndpoint(t *testing.T, expiresIn int) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var issued int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, ok := r.BasicAuth()
+		if !ok || clientID != "terraform" || clientSecret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		if r.PostFormValue("grant_type") != "client_credentials" || r.PostFormValue("scope") != "resumes:read resumes:write" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_request"}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(
+			w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":%d}`,
+			atomic.AddInt32(&issued, 1), expiresIn,
+		)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &issued
+}
+
+func TestClientCredentials(t *testing.T) {
+	server, issued := newTokenEndpoint(t, 60)
+	clock := &testClock{now: time.Now()}
+
+	source := &clientCredentials{
+		tokenURL:     server.URL,
+		clientID:     "terraform",
+		clientSecret: "secret",
+		scopes:       []string{"resumes:read", "resumes:write"},
+		httpClient:   http.DefaultClient,
+		now:          clock.Now,
+	}
+
+	steps := []struct {
+		advance  time.Duration
+		expected string
+	}{
+		{advance: 0, expected: "token-1"},
+		{advance: 20 * time.Second, expected: "token-1"},
+		// Refreshed 30 seconds before the token expires.
+		{advance: 15 * time.Second, expected: "token-2"},
+		{advance: time.Second, expected: "token-2"},
+	}
+
+	for i, step := range steps {
+		clock.now = clock.now.Add(step.advance)
+
+		token, err := source.Token(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != step.expected {
+			t.Errorf("step %d: expected %s, got %s", i, step.expected, token)
+		}
+	}
+
+	if *issued != 2 {
+		t.Errorf("expected 2 tokens to be issued, got %d", *issued)
+	}
+}
+
+func TestClientCredentialsError(t *testing.T) {
+	server, _ := newTokenEndpoint(t, 60)
+
+	source := &clientCredentials{
+		tokenURL:     server.URL,
+		clientID:     "terraform",
+		clientSecret: "wrong",
+		httpClient:   http.DefaultClient,
+		now:          time.Now,
+	}
+
+	_, err := source.Token(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "invalid_client") {
+		t.Errorf("expected invalid_client error, got %v", err)
+	}
+}
+
+func TestClientWithClientCredentials(t *testing.T) {
+	tokenEndpoint, _ := newTokenEndpoint(t, 3600)
+
+	var authorization string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer api.Close()
+
+	c := newClient(api.URL, "", withTokenSource(&clientCredentials{
+		tokenURL:     tokenEndpoint.URL,
+		clientID:     "terraform",
+		clientSecret: "secret",
+		scopes:       []string{"resumes:read", "resumes:write"},
+		httpClient:   http.DefaultClient,
+		now:          time.Now,
+	}))
+
+	resp, err := c.Get(context.Background(), "/info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if authorization != "Bearer token-1" {
+		t.Errorf("expected token from the token endpoint, got %q", authorization)
+	}
+}
+
+func TestTokenFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("first\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	source := &tokenFile{path: path}
+	if token, err := source.Token(context.Background()); err != nil || token != "first" {
+		t.Fatalf("expected first, got %q (%v)", token, err)
+	}
+
+	if err := os.WriteFile(path, []byte("second\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// Make sure the change is visible on file systems with coarse timestamps.
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	if token, err := source.Token(context.Background()); err != nil || token != "second" {
+		t.Fatalf("expected second, got %q (%v)", token, err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := source.Token(context.Background()); err == nil {
+		t.Error("expected error for missing token file")
+	}
+}
+
+func TestTokenCommand(t *testing.T) {
+	dir := t.TempDir()
+	runs := filepath.Join(dir, "runs")
+	clock := &testClock{now: time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)}
+
+	script := fmt.Sprintf(
+		`echo run >> %s; echo '{"token": "t'$(wc -l < %s | tr -d " ")'", "expires_at": "2023-07-01T13:00:00Z"}'`,
+		runs, runs,
+	)
+	source := &tokenCommand{args: []string{"sh", "-c", script}, now: clock.Now}
+
+	steps := []struct {
+		now      time.Time
+		expected string
+	}{
+		{now: clock.now, expected: "t1"},
+		{now: clock.now.Add(30 * time.Minute), expected: "t1"},
+		{now: clock.now.Add(59*time.Minute + 45*time.Second), expected: "t2"},
+	}
+
+	for i, step := range steps {
+		clock.now = step.now
+
+		token, err := source.Token(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != step.expected {
+			t.Errorf("step %d: expected %s, got %s", i, step.expected, token)
+		}
+	}
+}
+
+func TestTokenCommandOutput(t *testing.T) {
+	tests := map[string]struct {
+		output   string
+		expected cachedToken
+		err      bool
+	}{
+		"plain": {
+			output:   "secret\n",
+			expected: cachedToken{token: "secret"},
+		},
+		"json": {
+			output: `{"token":"secret","expires_at":"2023-07-01T13:00:00Z"}`,
+			expected: cachedToken{
+				token:  "secret",
+				expiry: time.Date(2023, 7, 1, 13, 0, 0, 0, time.UTC),
+			},
+		},
+		"json without expiry": {
+			output:   `{"token":"secret"}`,
+			expected: cachedToken{token: "secret"},
+		},
+		"json without token": {output: `{"expires_at":"2023-07-01T13:00:00Z"}`, err: true},
+		"invalid json":       {output: `{"token":`, err: true},
+		"empty":              {output: "\n", err: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, err := parseTokenCommandOutput([]byte(test.output))
+			if test.err != (err != nil) {
+				t.Fatalf("expected error %t, got %v", test.err, err)
+			}
+			if !actual.expiry.Equal(test.expected.expiry) || actual.token != test.expected.token {
+				t.Errorf("expected %+v, got %+v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestTokenCommandError(t *testing.T) {
+	source := &tokenCommand{args: []string{"sh", "-c", "echo denied >&2; exit 1"}, now: time.Now}
+
+	_, err := source.Token(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Errorf("expected error containing stderr, got %v", err)
+	}
+}
@@ -0,0 +1,355 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/lagerfeuer/terraform-provider-resume/internal/resumeapi"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// cassetteDir holds the cassettes of the acceptance tests, one per test.
+const cassetteDir = "testdata/cassettes"
+
+// cassetteRequestHeaders are the only request headers written to cassettes,
+// the Authorization header in particular is never stored.
+var cassetteRequestHeaders = []string{"Content-Type", "Idempotency-Key", "If-Match", "If-None-Match"}
+
+// cassette is a recording of API interactions.
+type cassette struct {
+	Interactions []*interaction `json:"interactions"`
+}
+
+type interaction struct {
+	Request struct {
+		Method string            `json:"method"`
+		URL    string            `json:"url"`
+		Header map[string]string `json:"header,omitempty"`
+		Body   string            `json:"body,omitempty"`
+	} `json:"request"`
+	Response struct {
+		Status int               `json:"status"`
+		Header map[string]string `json:"header,omitempty"`
+		Body   string            `json:"body,omitempty"`
+	} `json:"response"`
+
+	replayed bool
+}
+
+// recorder records interactions with the API to a cassette, or replays them
+// from it without contacting the API.
+type recorder struct {
+	replaying bool
+
+	mu       sync.Mutex
+	cassette cassette
+	// position is the index of the interaction after the last write which
+	// was replayed.
+	position int
+	// secrets are scrubbed from recorded interactions.
+	secrets []string
+}
+
+// newRecorder returns a recorder which records interactions.
+func newRecorder() *recorder {
+	return &recorder{}
+}
+
+// loadRecorder returns a recorder which replays the cassette at file.
+func loadRecorder(file string) (*recorder, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &recorder{replaying: true}
+	if err := json.Unmarshal(data, &r.cassette); err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %w", file, err)
+	}
+	return r, nil
+}
+
+// Transport returns a transport which records the interactions made through
+// next, or replays them. Every provider instance of a test gets its own
+// transport sharing the recorder.
+func (r *recorder) Transport(next http.RoundTripper) http.RoundTripper {
+	return &recorderTransport{recorder: r, next: next}
+}
+
+type recorderTransport struct {
+	*recorder
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *recorderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if t.replaying {
+		return t.replay(req, body)
+	}
+	return t.record(t.next, req, body)
+}
+
+func (r *recorder) record(next http.RoundTripper, req *http.Request, body []byte) (*http.Response, error) {
+	if token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "); token != "" {
+		r.mu.Lock()
+		r.secrets = append(r.secrets, token)
+		r.mu.Unlock()
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	i := &interaction{}
+	i.Request.Method = req.Method
+	i.Request.URL = req.URL.RequestURI()
+	i.Request.Body = string(body)
+	i.Request.Header = map[string]string{}
+	for _, key := range cassetteRequestHeaders {
+		if value := req.Header.Get(key); value != "" {
+			i.Request.Header[key] = value
+		}
+	}
+	i.Response.Status = resp.StatusCode
+	i.Response.Body = string(respBody)
+	i.Response.Header = map[string]string{}
+	for key := range resp.Header {
+		if key != "Set-Cookie" {
+			i.Response.Header[key] = resp.Header.Get(key)
+		}
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, i)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// replay responds with an interaction matching req. Writes are replayed
+// once each in the order they were recorded. Terraform versions differ in
+// how often they configure the provider and refresh, so reads are replayed
+// from those recorded between the last write and the next one: each once in
+// order, and the last one again once they are used up.
+func (r *recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	read := req.Method == http.MethodGet
+	var last *interaction
+	for n, i := range r.cassette.Interactions[r.position:] {
+		if read && i.Request.Method != http.MethodGet {
+			break
+		}
+		if !i.matches(req, body) {
+			continue
+		}
+		if i.replayed {
+			last = i
+			continue
+		}
+
+		i.replayed = true
+		if !read {
+			r.position += n + 1
+		}
+		return i.response(req), nil
+	}
+
+	if read && last != nil {
+		return last.response(req), nil
+	}
+	return nil, fmt.Errorf("no recorded interaction for %s %s", req.Method, req.URL.RequestURI())
+}
+
+// matches reports whether i was recorded for a request with the method,
+// URL, body and conditions of req.
+func (i *interaction) matches(req *http.Request, body []byte) bool {
+	return i.Request.Method == req.Method && i.Request.URL == req.URL.RequestURI() &&
+		i.Request.Body == string(body) &&
+		i.Request.Header["If-Match"] == req.Header.Get("If-Match") &&
+		i.Request.Header["If-None-Match"] == req.Header.Get("If-None-Match")
+}
+
+// response returns the recorded response of i to req.
+func (i *interaction) response(req *http.Request) *http.Response {
+	header := http.Header{}
+	for key, value := range i.Response.Header {
+		header.Set(key, value)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.Response.Status, http.StatusText(i.Response.Status)),
+		StatusCode:    i.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(i.Response.Body)),
+		ContentLength: int64(len(i.Response.Body)),
+		Request:       req,
+	}
+}
+
+// Save writes the recorded interactions to file with every secret scrubbed.
+func (r *recorder) Save(file string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	for _, secret := range r.secrets {
+		data = bytes.ReplaceAll(data, []byte(secret), []byte("REDACTED"))
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(data, '\n'), 0o644)
+}
+
+// testAccRecorder returns the recorder of the acceptance test t depending
+// on RESUME_API_CASSETTE: "record" records the interactions with the API at
+// RESUME_API_ENDPOINT to the cassette of t, "replay" replays them. It
+// defaults to replay if RESUME_API_ENDPOINT is not set, the API is used
+// directly otherwise and the returned recorder is nil.
+func testAccRecorder(t *testing.T) *recorder {
+	t.Helper()
+
+	file := filepath.Join(cassetteDir, t.Name()+".json")
+	switch testAccCassetteMode() {
+	case "record":
+		r := newRecorder()
+		t.Cleanup(func() {
+			if t.Skipped() {
+				return
+			}
+			if err := r.Save(file); err != nil {
+				t.Errorf("saving cassette: %v", err)
+			}
+		})
+		return r
+	case "replay":
+		r, err := loadRecorder(file)
+		if errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("No cassette at %s, record it with RESUME_API_CASSETTE=record against the API", file)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	return nil
+}
+
+func testAccCassetteMode() string {
+	if mode := os.Getenv("RESUME_API_CASSETTE"); mode != "" {
+		return mode
+	}
+	if os.Getenv("RESUME_API_ENDPOINT") == "" {
+		return "replay"
+	}
+	return ""
+}
+
+func TestRecorder(t *testing.T) {
+	api := newFakeAPI(t)
+	file := filepath.Join(t.TempDir(), "cassette.json")
+	ctx := context.Background()
+
+	// run creates a resume and reads it before and after it changed.
+	run := func(c *client, change func()) []resumeapi.Resume {
+		t.Helper()
+		r := resumeapi.New(c)
+
+		created, _, diags := r.CreateResume(ctx, resumeapi.Resume{Name: "Jane Doe"}, "abc")
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		first, _, diags := r.GetResume(ctx, "1", resumeapi.GetResumeOptions{})
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		change()
+		second, _, diags := r.GetResume(ctx, "1", resumeapi.GetResumeOptions{})
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		return []resumeapi.Resume{*created, *first, *second}
+	}
+
+	rec := newRecorder()
+	recorded := run(
+		api.client(withHTTPClient(&http.Client{Transport: rec.Transport(http.DefaultTransport)})),
+		func() { api.change(1, "name", "John Doe") },
+	)
+	if err := rec.Save(file); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "Bearer") || strings.Contains(string(data), fakeAPIToken) {
+		t.Errorf("expected the token to be scrubbed from the cassette:\n%s", data)
+	}
+
+	api.Close()
+	rec, err = loadRecorder(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newClient("http://resume.invalid", "replay", withRetry(0, 0),
+		withHTTPClient(&http.Client{Transport: rec.Transport(nil)}))
+	replayed := run(c, func() {})
+
+	if !reflect.DeepEqual(recorded, replayed) {
+		t.Errorf("expected replayed resumes %+v, got %+v", recorded, replayed)
+	}
+	if recorded[2].Name != "John Doe" {
+		t.Errorf("expected the change to be recorded, got %+v", recorded[2])
+	}
+
+	// Reads beyond the recorded ones get the last response again, writes
+	// are only replayed once.
+	again, _, diags := resumeapi.New(c).GetResume(ctx, "1", resumeapi.GetResumeOptions{})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if !reflect.DeepEqual(*again, recorded[2]) {
+		t.Errorf("expected the last response %+v to be replayed again, got %+v", recorded[2], *again)
+	}
+	_, _, diags = resumeapi.New(c).CreateResume(ctx, resumeapi.Resume{Name: "Jane Doe"}, "abc")
+	if !diags.HasError() || !strings.Contains(errorDetail(diags), "no recorded interaction for POST /resumes") {
+		t.Errorf("expected missing interaction error, got %v", diags)
+	}
+}
@@ -3,44 +3,147 @@ package provider
 // Source: https://github.com/BetterStackHQ/terraform-provider-better-uptime/blob/master/internal/provider/client.go
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/net/context/ctxhttp"
 	"io"
-	"log"
+	"math/rand"
+	"net"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultMaxRetries   = 3
+	defaultRetryMinWait = 500 * time.Millisecond
+	defaultRetryMaxWait = 30 * time.Second
+	// maxRetriesLimit bounds max_retries, which multiplies the time an
+	// operation may take.
+	maxRetriesLimit = 10
+
+	defaultRequestTimeout = time.Minute
 )
 
 type client struct {
-	baseURL    string
-	token      string
+	endpoints  *endpointPool
+	tokens     tokenSource
 	httpClient *http.Client
 	userAgent  string
+	// headers are sent with every request, e.g. for an API gateway.
+	headers http.Header
+
+	maxRetries   int
+	retryMinWait time.Duration
+	retryMaxWait time.Duration
+
+	// requestTimeout bounds every attempt of a request, zero means no
+	// timeout.
+	requestTimeout time.Duration
+
+	// limiter is shared by every resource and data source using the client,
+	// it is nil if rate limiting is disabled.
+	limiter *adaptiveLimiter
 }
 
 type option func(c *client)
 
-//nolint:unused
 func withHTTPClient(httpClient *http.Client) option {
 	return func(c *client) {
 		c.httpClient = httpClient
 	}
 }
 
+// withEndpoints replaces the base URL passed to newClient with the base URLs
+// of several instances of the API, see endpointPool.
+func withEndpoints(baseURLs []string) option {
+	return func(c *client) {
+		c.endpoints = newEndpointPool(baseURLs)
+	}
+}
+
+// withTokenSource replaces the static token passed to newClient.
+func withTokenSource(tokens tokenSource) option {
+	return func(c *client) {
+		c.tokens = tokens
+	}
+}
+
 func withUserAgent(userAgent string) option {
 	return func(c *client) {
 		c.userAgent = userAgent
 	}
 }
 
-func newClient(baseURL, token string, opts ...option) *client {
-	baseURL = strings.TrimSuffix(baseURL, "/")
+// managedHeaders are set by the client itself and cannot be overridden
+// through withHeaders.
+var managedHeaders = []string{
+	"Authorization", "Content-Type", "Idempotency-Key", "If-Match", "If-None-Match", "User-Agent",
+}
 
+// withHeaders sends headers with every request. The headers of a single
+// request and the ones the client manages itself, such as Authorization,
+// take precedence.
+func withHeaders(headers map[string]string) option {
+	return func(c *client) {
+		c.headers = http.Header{}
+		for key, value := range headers {
+			c.headers.Set(key, value)
+		}
+	}
+}
+
+// withRetry configures how often failed requests are retried and the
+// upper bound for the wait between two attempts.
+func withRetry(maxRetries int, maxWait time.Duration) option {
+	return func(c *client) {
+		c.maxRetries = maxRetries
+		c.retryMaxWait = maxWait
+	}
+}
+
+// withRequestTimeout bounds every attempt of a request by timeout. Zero
+// disables the timeout.
+func withRequestTimeout(timeout time.Duration) option {
+	return func(c *client) {
+		c.requestTimeout = timeout
+	}
+}
+
+// withRateLimit limits the client to requestsPerSecond with bursts of up to
+// burst requests. A rate of zero disables rate limiting, which is the
+// default.
+func withRateLimit(requestsPerSecond float64, burst int) option {
+	return func(c *client) {
+		if requestsPerSecond <= 0 {
+			c.limiter = nil
+			return
+		}
+		c.limiter = newAdaptiveLimiter(requestsPerSecond, burst)
+	}
+}
+
+func newClient(baseURL, token string, opts ...option) *client {
 	c := client{
-		baseURL:    baseURL,
-		token:      token,
-		httpClient: http.DefaultClient,
+		endpoints:      newEndpointPool([]string{baseURL}),
+		tokens:         staticToken(token),
+		httpClient:     http.DefaultClient,
+		maxRetries:     defaultMaxRetries,
+		retryMinWait:   defaultRetryMinWait,
+		retryMaxWait:   defaultRetryMaxWait,
+		requestTimeout: defaultRequestTimeout,
 	}
 
 	for _, opt := range opts {
@@ -51,31 +154,204 @@ func newClient(baseURL, token string, opts ...option) *client {
 }
 
 func (c *client) Get(ctx context.Context, path string) (*http.Response, error) {
-	return c.do(ctx, http.MethodGet, path, nil)
+	return c.do(ctx, http.MethodGet, path, nil, nil)
 }
 
-func (c *client) Post(ctx context.Context, path string, body io.Reader) (*http.Response, error) {
-	return c.do(ctx, http.MethodPost, path, body)
+// Post sends body to path. The API only creates one resource per
+// idempotencyKey, which makes it safe to retry the request.
+func (c *client) Post(ctx context.Context, path string, body io.Reader, idempotencyKey string) (*http.Response, error) {
+	header := http.Header{}
+	if idempotencyKey != "" {
+		header.Set("Idempotency-Key", idempotencyKey)
+	}
+	return c.do(ctx, http.MethodPost, path, body, header)
 }
 
 func (c *client) Patch(ctx context.Context, path string, body io.Reader) (*http.Response, error) {
-	log.Print("XXX PATCH XXX")
-	log.Print(path)
-	return c.do(ctx, http.MethodPatch, path, body)
+	return c.do(ctx, http.MethodPatch, path, body, nil)
 }
 
 func (c *client) Delete(ctx context.Context, path string) (*http.Response, error) {
-	return c.do(ctx, http.MethodDelete, path, nil)
+	return c.do(ctx, http.MethodDelete, path, nil, nil)
 }
 
-func (c *client) do(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
-	url := fmt.Sprintf("%s%s", c.baseURL, path)
-	req, err := http.NewRequest(method, url, body)
+func (c *client) do(
+	ctx context.Context, method, path string, body io.Reader, header http.Header,
+) (resp *http.Response, err error) {
+	ctx, span := startSpan(ctx, "HTTP "+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", method),
+			attribute.String("url.path", path),
+		),
+	)
+	defer func() { endHTTPSpan(span, resp, err) }()
+
+	// The body is buffered so it can be sent again when the request is retried.
+	var payload []byte
+	if body != nil {
+		if payload, err = io.ReadAll(body); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx = withHTTPLogging(ctx, c.headers)
+
+	// Requests sent to another endpoint after a failover do not count as
+	// retries.
+	failovers := 0
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			span.SetAttributes(attribute.Int("http.resend_count", attempt))
+		}
+
+		endpoint, baseURL := c.endpoints.Current()
+		req, err := c.newRequest(ctx, baseURL, method, path, payload, header)
+		if err != nil {
+			return nil, err
+		}
+		span.SetAttributes(attribute.String("server.address", req.URL.Host))
+
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		logCtx := tflog.SubsystemSetField(ctx, httpLogSubsystem, "endpoint", baseURL)
+		logRequest(logCtx, req, payload, attempt)
+
+		start := time.Now()
+		var logBody func(body []byte, truncated bool)
+		if traceHTTPBodies() {
+			logBody = func(body []byte, truncated bool) { logResponseBody(logCtx, req, body, truncated) }
+		}
+		resp, respBody, err := c.send(ctx, req, logBody)
+		logResponse(logCtx, req, resp, respBody, err, time.Since(start), attempt)
+		c.adaptRateLimit(resp)
+
+		if failovers < len(c.endpoints.urls)-1 && ctx.Err() == nil && endpointFailed(req, resp, err) &&
+			c.endpoints.Failover(ctx, endpoint, c.probe) {
+			failovers++
+			continue
+		}
+
+		if retries := attempt - failovers; retries < c.maxRetries && c.shouldRetry(ctx, req, resp, err) {
+			wait := c.backoff(retries, resp)
+			if resp != nil {
+				// Keep-Alive.
+				_, _ = io.Copy(io.Discard, resp.Body)
+				_ = resp.Body.Close()
+			}
+
+			if err := sleep(ctx, wait); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return nil, newAPIError(method, path, resp)
+		}
+
+		return resp, nil
+	}
+}
+
+// endHTTPSpan records the outcome of client.do on span and ends it. The
+// error message of an APIError is left out as it contains the response body.
+func endHTTPSpan(span trace.Span, resp *http.Response, err error) {
+	var apiErr *APIError
+	switch {
+	case errors.As(err, &apiErr):
+		span.SetAttributes(attribute.Int("http.response.status_code", apiErr.StatusCode))
+		span.SetStatus(codes.Error, fmt.Sprintf("%s %s returned %d", apiErr.Method, apiErr.Path, apiErr.StatusCode))
+	case err != nil:
+		span.SetStatus(codes.Error, err.Error())
+	default:
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	}
+	span.End()
+}
+
+// send makes a single attempt of req, bounded by requestTimeout. The body of
+// a successful response is passed on unread and given to logBody, unless it
+// is nil, once it was read, the attempt ends when it is closed. Other
+// response bodies are buffered and returned, so they can be logged and read
+// again.
+func (c *client) send(
+	ctx context.Context, req *http.Request, logBody func(body []byte, truncated bool),
+) (*http.Response, []byte, error) {
+	var attemptCtx context.Context
+	var cancel context.CancelFunc
+	if c.requestTimeout > 0 {
+		attemptCtx, cancel = context.WithTimeout(ctx, c.requestTimeout)
+	} else {
+		attemptCtx, cancel = context.WithCancel(ctx)
+	}
+
+	resp, err := ctxhttp.Do(attemptCtx, c.httpClient, req)
+	if err == nil && resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		resp.Body = &loggedBody{ReadCloser: resp.Body, log: logBody, done: cancel}
+		return resp, nil, nil
+	}
+	defer cancel()
+
+	var body []byte
+	if err == nil {
+		if body, err = bufferResponseBody(resp); err != nil {
+			resp = nil
+		}
+	}
+
+	if err != nil && ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+		err = &TimeoutError{Method: req.Method, Path: req.URL.Path, Timeout: c.requestTimeout}
+	}
+
+	return resp, body, err
+}
+
+func (c *client) adaptRateLimit(resp *http.Response) {
+	if c.limiter == nil || resp == nil {
+		return
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		c.limiter.Throttled()
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+		c.limiter.Succeeded()
+	}
+}
+
+func (c *client) newRequest(
+	ctx context.Context, baseURL, method, path string, payload []byte, header http.Header,
+) (*http.Request, error) {
+	url := fmt.Sprintf("%s%s", baseURL, path)
+	req, err := http.NewRequest(method, url, bytes.NewReader(payload))
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	token, err := c.tokens.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range c.headers {
+		req.Header[key] = values
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
 
 	if c.userAgent != "" {
 		req.Header.Set("User-Agent", c.userAgent)
@@ -85,6 +361,282 @@ func (c *client) do(ctx context.Context, method, path string, body io.Reader) (*
 		req.Header.Set("Content-Type", "application/json")
 	}
 
-	// TODO check for return code and return error if not in range 200-299
-	return ctxhttp.Do(ctx, c.httpClient, req)
+	return req, nil
+}
+
+// shouldRetry reports whether the outcome of req is worth another attempt.
+// Requests which are not idempotent are only retried when the server
+// certainly did not process them.
+func (c *client) shouldRetry(ctx context.Context, req *http.Request, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		if isDialError(err) {
+			return true
+		}
+		return isRetryableNetworkError(err) && isIdempotent(req)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return isIdempotent(req)
+	}
+
+	return false
+}
+
+// isIdempotent reports whether req can be sent twice without side effects.
+// PATCH is included since the API only ever replaces attribute values, POST
+// only if it carries an Idempotency-Key.
+func isIdempotent(req *http.Request) bool {
+	if req.Header.Get("Idempotency-Key") != "" {
+		return true
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isDialError reports whether err occurred before the request was sent.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func isRetryableNetworkError(err error) bool {
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// backoff returns the time to wait before the next attempt. A Retry-After
+// header sent by the server takes precedence over the exponential backoff,
+// both are capped by retryMaxWait.
+func (c *client) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return minDuration(wait, c.retryMaxWait)
+		}
+	}
+
+	wait := c.retryMinWait
+	for i := 0; i < attempt && wait < c.retryMaxWait; i++ {
+		if wait >= c.retryMaxWait/2 {
+			// Doubling again could overflow.
+			wait = c.retryMaxWait
+			break
+		}
+		wait *= 2
+	}
+	if wait <= 0 || wait > c.retryMaxWait {
+		wait = c.retryMaxWait
+	}
+
+	// Full jitter in the upper half, so concurrent retries spread out.
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// parseRetryAfter understands both forms of the Retry-After header, delay
+// seconds and an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// TimeoutError is returned by the client if the API did not respond within
+// the request timeout.
+type TimeoutError struct {
+	Method  string
+	Path    string
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s %s timed out after %s", e.Method, e.Path, e.Timeout)
+}
+
+// Unwrap makes the error a timeout for errors.Is and errors.As, so it is
+// retried like one.
+func (e *TimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// APIError is returned by the client for every response outside the 2xx range.
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	// RequestID is the value of the X-Request-Id header set by Rails, if any.
+	RequestID string
+	// Errors holds the decoded Rails validation errors, keyed by attribute name.
+	Errors map[string][]string
+	Body   []byte
+}
+
+// newAPIError consumes and closes the body of resp.
+func newAPIError(method, path string, resp *http.Response) *APIError {
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	return &APIError{
+		Method:     method,
+		Path:       path,
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-Id"),
+		Errors:     decodeRailsErrors(body),
+		Body:       body,
+	}
+}
+
+// hasStatus reports whether err is an APIError with one of statuses.
+func hasStatus(err error, statuses ...int) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	for _, status := range statuses {
+		if apiErr.StatusCode == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s %s returned %d", e.Method, e.Path, e.StatusCode)
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " (request ID %s)", e.RequestID)
+	}
+
+	if len(e.Errors) > 0 {
+		b.WriteString(": ")
+		b.WriteString(strings.Join(e.Messages(), ", "))
+	} else if len(e.Body) > 0 {
+		b.WriteString(": ")
+		b.Write(e.Body)
+	}
+
+	return b.String()
+}
+
+// Messages returns every field error as "<field> <message>", sorted by field.
+func (e *APIError) Messages() []string {
+	fields := make([]string, 0, len(e.Errors))
+	for field := range e.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var messages []string
+	for _, field := range fields {
+		for _, msg := range e.Errors[field] {
+			messages = append(messages, fieldErrorMessage(field, msg))
+		}
+	}
+
+	return messages
+}
+
+func fieldErrorMessage(field, msg string) string {
+	if field == "base" {
+		return msg
+	}
+	return fmt.Sprintf("%s %s", field, msg)
+}
+
+// decodeRailsErrors understands the error bodies rendered by Rails, i.e.
+// `{"name":["can't be blank"]}`, the same wrapped in `{"errors": ...}` and
+// `{"status": 404, "error": "Not Found"}`. Anything else yields nil.
+func decodeRailsErrors(body []byte) map[string][]string {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil
+	}
+
+	if wrapped, ok := raw["errors"]; ok && len(raw) == 1 {
+		if err := json.Unmarshal(wrapped, &raw); err != nil {
+			return decodeRailsErrorMessages("base", wrapped)
+		}
+	}
+
+	if msg, ok := raw["error"]; ok {
+		return decodeRailsErrorMessages("base", msg)
+	}
+
+	errs := make(map[string][]string, len(raw))
+	for field, value := range raw {
+		if msgs := decodeRailsErrorMessages(field, value)[field]; len(msgs) > 0 {
+			errs[field] = msgs
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func decodeRailsErrorMessages(field string, value json.RawMessage) map[string][]string {
+	var msgs []string
+	if err := json.Unmarshal(value, &msgs); err == nil {
+		return map[string][]string{field: msgs}
+	}
+
+	var msg string
+	if err := json.Unmarshal(value, &msg); err == nil && msg != "" {
+		return map[string][]string{field: {msg}}
+	}
+
+	return nil
 }
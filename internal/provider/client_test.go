@@ -0,0 +1,424 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-123")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"name":["can't be blank"],"phone_number":["is invalid","is too short"]}`))
+	}))
+	defer server.Close()
+
+	c := newClient(server.URL, "foo")
+	resp, err := c.Post(context.Background(), "/resumes", nil, "")
+	if resp != nil {
+		t.Fatalf("expected no response, got %d", resp.StatusCode)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %T: %v", err, err)
+	}
+
+	if apiErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("expected status 422, got %d", apiErr.StatusCode)
+	}
+	if apiErr.RequestID != "req-123" {
+		t.Errorf("expected request ID req-123, got %q", apiErr.RequestID)
+	}
+	expected := map[string][]string{
+		"name":         {"can't be blank"},
+		"phone_number": {"is invalid", "is too short"},
+	}
+	if !reflect.DeepEqual(apiErr.Errors, expected) {
+		t.Errorf("expected errors %v, got %v", expected, apiErr.Errors)
+	}
+	if len(apiErr.Body) == 0 {
+		t.Error("expected raw body to be kept")
+	}
+
+	msg := "POST /resumes returned 422 (request ID req-123): name can't be blank, phone_number is invalid, phone_number is too short"
+	if apiErr.Error() != msg {
+		t.Errorf("expected message %q, got %q", msg, apiErr.Error())
+	}
+}
+
+func TestClientSuccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c := newClient(server.URL, "foo")
+	resp, err := c.Delete(context.Background(), "/resumes/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("expected status 204, got %d", resp.StatusCode)
+	}
+}
+
+func TestClientHeaders(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	c := newClient(server.URL, "foo", withUserAgent("terraform-provider-resume/1.2.3"), withHeaders(map[string]string{
+		"X-Tenant":          "acme",
+		"x-api-gateway-key": "secret",
+		"Idempotency-Key":   "static",
+	}))
+	resp, err := c.Post(context.Background(), "/resumes", strings.NewReader("{}"), "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	expected := map[string]string{
+		"X-Tenant":          "acme",
+		"X-Api-Gateway-Key": "secret",
+		"Idempotency-Key":   "abc",
+		"Authorization":     "Bearer foo",
+		"User-Agent":        "terraform-provider-resume/1.2.3",
+	}
+	for key, value := range expected {
+		if header.Get(key) != value {
+			t.Errorf("expected header %s %q, got %q", key, value, header.Get(key))
+		}
+	}
+}
+
+func TestDecodeRailsErrors(t *testing.T) {
+	tests := map[string]struct {
+		body     string
+		expected map[string][]string
+	}{
+		"fields": {
+			body:     `{"name":["can't be blank"]}`,
+			expected: map[string][]string{"name": {"can't be blank"}},
+		},
+		"wrapped fields": {
+			body:     `{"errors":{"website":["is invalid"]}}`,
+			expected: map[string][]string{"website": {"is invalid"}},
+		},
+		"wrapped messages": {
+			body:     `{"errors":["Name can't be blank"]}`,
+			expected: map[string][]string{"base": {"Name can't be blank"}},
+		},
+		"error": {
+			body:     `{"status":404,"error":"Not Found"}`,
+			expected: map[string][]string{"base": {"Not Found"}},
+		},
+		"html": {
+			body:     `<html>Bad Gateway</html>`,
+			expected: nil,
+		},
+		"empty": {
+			body:     ``,
+			expected: nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual := decodeRailsErrors([]byte(test.body))
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+// newFlakyServer returns a server which responds with status to the first
+// failures requests and with 200 afterwards. It counts every request.
+func newFlakyServer(t *testing.T, failures int, status int, header http.Header) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if int(atomic.AddInt32(&attempts, 1)) <= failures {
+			for key, values := range header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &attempts
+}
+
+func newRetryingClient(baseURL string, maxRetries int) *client {
+	c := newClient(baseURL, "foo", withRetry(maxRetries, 10*time.Millisecond))
+	c.retryMinWait = time.Millisecond
+	return c
+}
+
+func TestClientRetry(t *testing.T) {
+	tests := map[string]struct {
+		method     string
+		header     http.Header
+		status     int
+		failures   int
+		maxRetries int
+		attempts   int32
+		success    bool
+	}{
+		"get recovers from 503": {
+			method: http.MethodGet, status: http.StatusServiceUnavailable,
+			failures: 2, maxRetries: 3, attempts: 3, success: true,
+		},
+		"get recovers from 502": {
+			method: http.MethodGet, status: http.StatusBadGateway,
+			failures: 3, maxRetries: 3, attempts: 4, success: true,
+		},
+		"get gives up": {
+			method: http.MethodGet, status: http.StatusBadGateway,
+			failures: 5, maxRetries: 2, attempts: 3, success: false,
+		},
+		"retries disabled": {
+			method: http.MethodGet, status: http.StatusServiceUnavailable,
+			failures: 1, maxRetries: 0, attempts: 1, success: false,
+		},
+		"get does not retry 404": {
+			method: http.MethodGet, status: http.StatusNotFound,
+			failures: 1, maxRetries: 3, attempts: 1, success: false,
+		},
+		"post retries 429": {
+			method: http.MethodPost, status: http.StatusTooManyRequests,
+			failures: 2, maxRetries: 3, attempts: 3, success: true,
+		},
+		"post retries 503": {
+			method: http.MethodPost, status: http.StatusServiceUnavailable,
+			failures: 1, maxRetries: 3, attempts: 2, success: true,
+		},
+		"post does not retry 502": {
+			method: http.MethodPost, status: http.StatusBadGateway,
+			failures: 1, maxRetries: 3, attempts: 1, success: false,
+		},
+		"post with idempotency key retries 502": {
+			method: http.MethodPost, header: http.Header{"Idempotency-Key": []string{"foo"}},
+			status: http.StatusBadGateway, failures: 1, maxRetries: 3, attempts: 2, success: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server, attempts := newFlakyServer(t, test.failures, test.status, nil)
+			c := newRetryingClient(server.URL, test.maxRetries)
+
+			resp, err := c.do(
+				context.Background(), test.method, "/resumes", strings.NewReader(`{"name":"foo"}`), test.header,
+			)
+			if resp != nil {
+				resp.Body.Close()
+			}
+
+			if test.success && err != nil {
+				t.Errorf("expected success, got %v", err)
+			}
+			if !test.success {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != test.status {
+					t.Errorf("expected *APIError with status %d, got %v", test.status, err)
+				}
+			}
+			if *attempts != test.attempts {
+				t.Errorf("expected %d attempts, got %d", test.attempts, *attempts)
+			}
+		})
+	}
+}
+
+func TestClientBackoff(t *testing.T) {
+	c := newClient("http://resume.invalid", "token")
+
+	for _, attempt := range []int{0, 1, 5, 40, 64, 1000} {
+		wait := c.backoff(attempt, nil)
+		if wait < 0 || wait > c.retryMaxWait {
+			t.Errorf("attempt %d: expected a wait between 0 and %s, got %s", attempt, c.retryMaxWait, wait)
+		}
+		if attempt >= 40 && wait < c.retryMaxWait/2 {
+			t.Errorf("attempt %d: expected at least half of %s, got %s", attempt, c.retryMaxWait, wait)
+		}
+	}
+}
+
+func TestClientRetryResendsBody(t *testing.T) {
+	var bodies []string
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := newRetryingClient(server.URL, 1)
+	resp, err := c.Patch(context.Background(), "/resumes/1", strings.NewReader(`{"name":"foo"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	expected := []string{`{"name":"foo"}`, `{"name":"foo"}`}
+	if !reflect.DeepEqual(bodies, expected) {
+		t.Errorf("expected bodies %v, got %v", expected, bodies)
+	}
+}
+
+func TestClientRetryConnectionReset(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			_ = conn.Close()
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := newRetryingClient(server.URL, 2)
+	resp, err := c.Get(context.Background(), "/info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if atomic.LoadInt32(&attempts) != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestClientRetryAfter(t *testing.T) {
+	server, attempts := newFlakyServer(
+		t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": []string{"1"}},
+	)
+
+	// The Retry-After of one second is capped by retryMaxWait.
+	c := newRetryingClient(server.URL, 1)
+	c.retryMaxWait = 50 * time.Millisecond
+
+	start := time.Now()
+	resp, err := c.Get(context.Background(), "/info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond || elapsed > time.Second {
+		t.Errorf("expected to wait for retryMaxWait, waited %s", elapsed)
+	}
+	if *attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", *attempts)
+	}
+}
+
+func TestClientRetryCanceled(t *testing.T) {
+	server, attempts := newFlakyServer(t, 10, http.StatusServiceUnavailable, nil)
+
+	c := newRetryingClient(server.URL, 10)
+	c.retryMinWait = time.Hour
+	c.retryMaxWait = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := c.Get(ctx, "/info"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if *attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", *attempts)
+	}
+}
+
+func TestClientRequestTimeout(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			<-r.Context().Done()
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := newRetryingClient(server.URL, 1)
+	c.requestTimeout = 20 * time.Millisecond
+
+	resp, err := c.Get(context.Background(), "/info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if atomic.LoadInt32(&attempts) != 2 {
+		t.Errorf("expected timed out request to be retried, got %d attempts", attempts)
+	}
+
+	atomic.StoreInt32(&attempts, 0)
+	c.maxRetries = 0
+
+	_, err = c.Get(context.Background(), "/info")
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected *TimeoutError, got %T: %v", err, err)
+	}
+	if msg := err.Error(); msg != "GET /info timed out after 20ms" {
+		t.Errorf("unexpected message %q", msg)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		"empty":   {value: "", ok: false},
+		"seconds": {value: "120", expected: 2 * time.Minute, ok: true},
+		"date":    {value: "Sat, 01 Jul 2023 12:00:30 GMT", expected: 30 * time.Second, ok: true},
+		"past":    {value: "Sat, 01 Jul 2023 11:00:00 GMT", expected: 0, ok: true},
+		"invalid": {value: "soon", ok: false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, ok := parseRetryAfter(test.value, now)
+			if ok != test.ok || actual != test.expected {
+				t.Errorf("expected (%s, %t), got (%s, %t)", test.expected, test.ok, actual, ok)
+			}
+		})
+	}
+}
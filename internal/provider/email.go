@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/lagerfeuer/terraform-provider-resume/internal/resumeapi"
+	"net/mail"
+	"strings"
+)
+
+var (
+	_ validator.String = emailValidator{}
+	_ validator.List   = primaryEmailValidator{}
+)
+
+type resumeEmailModel struct {
+	Address types.String `tfsdk:"address"`
+	Label   types.String `tfsdk:"label"`
+	Primary types.Bool   `tfsdk:"primary"`
+}
+
+// resumeEmailAttributes are the attributes of the emails of resume_resume.
+func resumeEmailAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"address": schema.StringAttribute{
+			Required: true,
+			Validators: []validator.String{
+				emailValidator{},
+			},
+		},
+		"label": schema.StringAttribute{
+			Description: "What the address is used for, e.g. \"work\"",
+			Optional:    true,
+		},
+		"primary": schema.BoolAttribute{
+			Description: "Whether this is the primary address, defaults to false",
+			Optional:    true,
+			Computed:    true,
+			Default:     booldefault.StaticBool(false),
+		},
+	}
+}
+
+// emailsToAPI returns nil if emails is.
+func emailsToAPI(emails []resumeEmailModel) *[]resumeapi.Email {
+	if emails == nil {
+		return nil
+	}
+
+	result := make([]resumeapi.Email, 0, len(emails))
+	for _, email := range emails {
+		result = append(result, resumeapi.Email{
+			Address: email.Address.ValueString(),
+			Label:   email.Label.ValueStringPointer(),
+			Primary: email.Primary.ValueBool(),
+		})
+	}
+	return &result
+}
+
+func emailsFromAPI(emails *[]resumeapi.Email) []resumeEmailModel {
+	if emails == nil {
+		return nil
+	}
+
+	result := make([]resumeEmailModel, 0, len(*emails))
+	for _, email := range *emails {
+		result = append(result, resumeEmailModel{
+			Address: types.StringValue(email.Address),
+			Label:   types.StringPointerValue(email.Label),
+			Primary: types.BoolValue(email.Primary),
+		})
+	}
+	return result
+}
+
+// emailValidator validates bare RFC 5322 email addresses, without a display
+// name.
+type emailValidator struct{}
+
+func (v emailValidator) Description(ctx context.Context) string {
+	return "value must be an RFC 5322 email address"
+}
+
+func (v emailValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v emailValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	address, err := mail.ParseAddress(value)
+	if err == nil && (address.Name != "" || strings.ContainsAny(value, "<>")) {
+		err = fmt.Errorf("display names are not supported")
+	}
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Email Address",
+			fmt.Sprintf("%q is not an email address like \"jane@example.com\": %v.", value, err),
+		)
+	}
+}
+
+// primaryEmailValidator validates that exactly one email is primary.
+type primaryEmailValidator struct{}
+
+func (v primaryEmailValidator) Description(ctx context.Context) string {
+	return "exactly one email must be primary"
+}
+
+func (v primaryEmailValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v primaryEmailValidator) ValidateList(ctx context.Context, req validator.ListRequest, resp *validator.ListResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	primary := 0
+	for _, element := range req.ConfigValue.Elements() {
+		email, ok := element.(types.Object)
+		if !ok || email.IsUnknown() {
+			return
+		}
+		// An unset primary defaults to false.
+		flag, ok := email.Attributes()["primary"].(types.Bool)
+		if !ok || flag.IsUnknown() {
+			return
+		}
+		if flag.ValueBool() {
+			primary++
+		}
+	}
+
+	if primary != 1 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Primary Email",
+			fmt.Sprintf("Exactly one email must have primary = true, got %d.", primary),
+		)
+	}
+}
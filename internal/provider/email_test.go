@@ -0,0 +1,148 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"reflect"
+	"testing"
+)
+
+// testEmail returns an email without a label if label is empty.
+func testEmail(address, label string, primary bool) resumeEmailModel {
+	email := resumeEmailModel{
+		Address: types.StringValue(address),
+		Label:   types.StringNull(),
+		Primary: types.BoolValue(primary),
+	}
+	if label != "" {
+		email.Label = types.StringValue(label)
+	}
+	return email
+}
+
+func TestEmailValidator(t *testing.T) {
+	cases := map[string]bool{
+		"michael@dundermifflin.com":           true,
+		"michael.scott+cv@dunder-mifflin.com": true,
+		"michael@localhost":                   true,
+		"michael":                             false,
+		"@dundermifflin.com":                  false,
+		"michael@":                            false,
+		"michael scott@dundermifflin.com":     false,
+		"Michael <michael@dundermifflin.com>": false,
+		"":                                    false,
+	}
+
+	for value, valid := range cases {
+		req := validator.StringRequest{Path: path.Root("address"), ConfigValue: types.StringValue(value)}
+		var resp validator.StringResponse
+		emailValidator{}.ValidateString(context.Background(), req, &resp)
+
+		if resp.Diagnostics.HasError() == valid {
+			t.Errorf("expected %q to be valid %t, got %v", value, valid, resp.Diagnostics)
+		}
+	}
+}
+
+func TestPrimaryEmailValidator(t *testing.T) {
+	attrTypes := map[string]attr.Type{
+		"address": types.StringType,
+		"label":   types.StringType,
+		"primary": types.BoolType,
+	}
+	email := func(primary types.Bool) attr.Value {
+		return types.ObjectValueMust(attrTypes, map[string]attr.Value{
+			"address": types.StringValue("michael@dundermifflin.com"),
+			"label":   types.StringNull(),
+			"primary": primary,
+		})
+	}
+	yes, no, unset := types.BoolValue(true), types.BoolValue(false), types.BoolNull()
+
+	cases := map[string]struct {
+		emails []attr.Value
+		valid  bool
+	}{
+		"single primary":       {emails: []attr.Value{email(yes)}, valid: true},
+		"primary among others": {emails: []attr.Value{email(unset), email(yes), email(no)}, valid: true},
+		"unknown primary":      {emails: []attr.Value{email(types.BoolUnknown()), email(yes)}, valid: true},
+		"no primary":           {emails: []attr.Value{email(unset), email(no)}},
+		"several primaries":    {emails: []attr.Value{email(yes), email(yes)}},
+		"empty":                {emails: []attr.Value{}},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := validator.ListRequest{
+				Path:        path.Root("emails"),
+				ConfigValue: types.ListValueMust(types.ObjectType{AttrTypes: attrTypes}, c.emails),
+			}
+			var resp validator.ListResponse
+			primaryEmailValidator{}.ValidateList(context.Background(), req, &resp)
+
+			if resp.Diagnostics.HasError() == c.valid {
+				t.Errorf("expected valid %t, got %v", c.valid, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestResumeResourceEmails(t *testing.T) {
+	api := newFakeAPI(t)
+	p := api.provider(api.config())
+
+	config := testResumePlan("Michael G Scott")
+	config.Id = types.StringNull()
+	config.PhoneNumberE164 = types.StringNull()
+	config.Emails = []resumeEmailModel{
+		testEmail("michael@dundermifflin.com", "work", true),
+		testEmail("michael@scott.com", "", false),
+	}
+	config.Emails[1].Primary = types.BoolNull()
+
+	plan, diags := p.planResume(nil, &config)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if primary := plan.Emails[1].Primary; primary.IsNull() || primary.ValueBool() {
+		t.Errorf("expected primary to default to false, got %s", primary)
+	}
+
+	created, private, diags := p.applyResume(nil, &plan, nil)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if !reflect.DeepEqual(created.Emails, plan.Emails) {
+		t.Errorf("expected created emails %+v, got %+v", plan.Emails, created.Emails)
+	}
+	expected := []interface{}{
+		map[string]interface{}{"address": "michael@dundermifflin.com", "label": "work", "primary": true},
+		map[string]interface{}{"address": "michael@scott.com", "primary": false},
+	}
+	if stored, _ := api.field(1, "emails"); !reflect.DeepEqual(stored, expected) {
+		t.Errorf("expected emails to be stored as %v, got %v", expected, stored)
+	}
+
+	planned := created
+	planned.Emails = []resumeEmailModel{testEmail("michael@scott.com", "personal", true)}
+	updated, private, diags := p.applyResume(&created, &planned, private)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if !reflect.DeepEqual(updated.Emails, planned.Emails) {
+		t.Errorf("expected updated emails %+v, got %+v", planned.Emails, updated.Emails)
+	}
+
+	planned = updated
+	planned.Emails = nil
+	cleared, _, diags := p.applyResume(&updated, &planned, private)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if stored, ok := api.field(1, "emails"); cleared.Emails != nil || !ok || stored != nil {
+		t.Errorf("expected emails to be cleared, got %+v stored as %v", cleared.Emails, stored)
+	}
+}
@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// endpointPool holds the base URLs of every instance of the API. The client
+// sticks to the current endpoint for as long as it is healthy and fails over
+// to the next endpoint which answers /info once it is not.
+type endpointPool struct {
+	urls []string
+
+	// mu is held during a failover, so concurrent requests wait for its
+	// outcome instead of probing the endpoints themselves.
+	mu      sync.Mutex
+	current int
+}
+
+func newEndpointPool(baseURLs []string) *endpointPool {
+	urls := make([]string, len(baseURLs))
+	for i, baseURL := range baseURLs {
+		urls[i] = strings.TrimSuffix(baseURL, "/")
+	}
+	return &endpointPool{urls: urls}
+}
+
+// Current returns the index and base URL of the endpoint requests are sent
+// to.
+func (p *endpointPool) Current() (int, string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.current, p.urls[p.current]
+}
+
+// Failover moves away from the endpoint with index failed to the next one
+// for which probe succeeds. It reports whether requests should be sent to
+// another endpoint, which is also the case if a concurrent request already
+// failed over.
+func (p *endpointPool) Failover(
+	ctx context.Context, failed int, probe func(ctx context.Context, baseURL string) error,
+) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.current != failed {
+		return true
+	}
+
+	for i := 1; i < len(p.urls); i++ {
+		next := (failed + i) % len(p.urls)
+		if err := probe(ctx, p.urls[next]); err != nil {
+			tflog.SubsystemWarn(ctx, httpLogSubsystem, "Skipping unhealthy endpoint", map[string]interface{}{
+				"endpoint": p.urls[next],
+				"error":    err.Error(),
+			})
+			continue
+		}
+
+		tflog.SubsystemWarn(ctx, httpLogSubsystem, "Failing over to another endpoint", map[string]interface{}{
+			"failed_endpoint": p.urls[failed],
+			"endpoint":        p.urls[next],
+		})
+		p.current = next
+		return true
+	}
+
+	return false
+}
+
+// probe checks the health of the endpoint at baseURL through /info.
+func (c *client) probe(ctx context.Context, baseURL string) error {
+	req, err := c.newRequest(ctx, baseURL, http.MethodGet, "/info", nil, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, _, err := c.send(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newAPIError(req.Method, req.URL.Path, resp)
+	}
+	return resp.Body.Close()
+}
+
+// endpointFailed reports whether the outcome of req means that the endpoint
+// rather than the request is at fault. The request may be sent to another
+// endpoint if it was not sent at all or is idempotent.
+func endpointFailed(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		if isDialError(err) {
+			return true
+		}
+		return isRetryableNetworkError(err) && isIdempotent(req)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotent(req)
+	}
+	return false
+}
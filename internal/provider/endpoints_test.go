@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// testEndpoint is an instance of the API which can fail on demand.
+type testEndpoint struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []string
+	// status is returned for every request if not zero.
+	status atomic.Int32
+}
+
+func newTestEndpoint(t *testing.T) *testEndpoint {
+	t.Helper()
+
+	e := &testEndpoint{}
+	e.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e.mu.Lock()
+		e.requests = append(e.requests, r.Method+" "+r.URL.Path)
+		e.mu.Unlock()
+
+		if status := e.status.Load(); status != 0 {
+			w.WriteHeader(int(status))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(e.Close)
+
+	return e
+}
+
+// Requests returns "<method> <path>" for every request received so far.
+func (e *testEndpoint) Requests() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.requests...)
+}
+
+func TestClientFailover(t *testing.T) {
+	primary := newTestEndpoint(t)
+	secondary := newTestEndpoint(t)
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	c := newClient(primary.URL, "foo", withEndpoints([]string{primary.URL, secondary.URL}), withRetry(0, 0))
+	get := func() {
+		t.Helper()
+		resp, err := c.Get(ctx, "/resumes/1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	get()
+	primary.Close()
+	get()
+	get()
+
+	if requests := primary.Requests(); len(requests) != 1 {
+		t.Errorf("expected a single request to the primary endpoint, got %v", requests)
+	}
+	expected := []string{"GET /info", "GET /resumes/1", "GET /resumes/1"}
+	if requests := secondary.Requests(); strings.Join(requests, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected requests %v to the secondary endpoint, got %v", expected, requests)
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var served []interface{}
+	for _, entry := range entries {
+		if entry["@message"] == "Received HTTP response" {
+			served = append(served, entry["endpoint"])
+		}
+	}
+	if len(served) != 3 || served[0] != primary.URL || served[1] != secondary.URL || served[2] != secondary.URL {
+		t.Errorf("expected responses from primary, secondary, secondary, got %v", served)
+	}
+}
+
+func TestClientFailoverSticky(t *testing.T) {
+	primary := newTestEndpoint(t)
+	secondary := newTestEndpoint(t)
+
+	c := newClient(primary.URL, "foo", withEndpoints([]string{primary.URL, secondary.URL}), withRetry(0, 0))
+
+	primary.status.Store(http.StatusServiceUnavailable)
+	resp, err := c.Get(context.Background(), "/resumes/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	// The primary endpoint recovers, but the client stays with the
+	// secondary one for the rest of the run.
+	primary.status.Store(0)
+	resp, err = c.Get(context.Background(), "/resumes/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if requests := primary.Requests(); len(requests) != 1 {
+		t.Errorf("expected a single request to the primary endpoint, got %v", requests)
+	}
+	if requests := secondary.Requests(); len(requests) != 3 {
+		t.Errorf("expected a probe and two requests to the secondary endpoint, got %v", requests)
+	}
+}
+
+func TestClientFailoverUnhealthy(t *testing.T) {
+	primary := newTestEndpoint(t)
+	secondary := newTestEndpoint(t)
+	primary.status.Store(http.StatusBadGateway)
+	secondary.status.Store(http.StatusBadGateway)
+
+	c := newClient(primary.URL, "foo", withEndpoints([]string{primary.URL, secondary.URL}), withRetry(0, 0))
+	_, err := c.Get(context.Background(), "/resumes/1")
+	if !hasStatus(err, http.StatusBadGateway) {
+		t.Fatalf("expected 502 from the primary endpoint, got %v", err)
+	}
+
+	if requests := secondary.Requests(); len(requests) != 1 || requests[0] != "GET /info" {
+		t.Errorf("expected only a probe of the secondary endpoint, got %v", requests)
+	}
+	if index, _ := c.endpoints.Current(); index != 0 {
+		t.Errorf("expected to stay with the primary endpoint, got %d", index)
+	}
+}
+
+func TestClientFailoverNotIdempotent(t *testing.T) {
+	primary := newTestEndpoint(t)
+	secondary := newTestEndpoint(t)
+	primary.status.Store(http.StatusBadGateway)
+
+	c := newClient(primary.URL, "foo", withEndpoints([]string{primary.URL, secondary.URL}), withRetry(0, 0))
+	_, err := c.Post(context.Background(), "/resumes", strings.NewReader("{}"), "")
+	if !hasStatus(err, http.StatusBadGateway) {
+		t.Fatalf("expected 502 from the primary endpoint, got %v", err)
+	}
+
+	if requests := secondary.Requests(); len(requests) != 0 {
+		t.Errorf("expected no requests to the secondary endpoint, got %v", requests)
+	}
+}
@@ -0,0 +1,393 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const fakeAPIToken = "foo"
+
+// fakeAPI is an in-memory stand-in for the Rails Resume API.
+type fakeAPI struct {
+	*httptest.Server
+	t *testing.T
+
+	mu      sync.Mutex
+	nextID  int64
+	resumes map[int64]map[string]interface{}
+	// versions counts the updates of every resume, its ETag is derived from
+	// it.
+	versions map[int64]int
+	// weakETags makes the fake API issue weak ETags like Rails does by
+	// default.
+	weakETags bool
+	// normalizeURLs makes the fake API store the website and profile URLs
+	// with a lower case scheme and host and without a trailing slash.
+	normalizeURLs bool
+	// idempotencyKeys maps the Idempotency-Key of every create to its
+	// request body and the created resume.
+	idempotencyKeys map[string]fakeIdempotentCreate
+	// dropCreateResponses is the number of creates which are committed
+	// without the client receiving a response.
+	dropCreateResponses int
+	// stallCreateResponses commits creates, but holds back their response
+	// until the client gives up.
+	stallCreateResponses bool
+	// failLists is the number of lists which fail with 503 Service
+	// Unavailable.
+	failLists int
+	// gone holds the resumes which were purged, the API responds with 410
+	// Gone for them.
+	gone map[int64]bool
+	// version is reported by /info.
+	version string
+	// stalled makes the fake API hang until the client gives up.
+	stalled  atomic.Bool
+	requests []string
+	// header is the header of the last request.
+	header http.Header
+}
+
+type fakeIdempotentCreate struct {
+	body string
+	id   int64
+}
+
+func newFakeAPI(t *testing.T) *fakeAPI {
+	t.Helper()
+
+	f := &fakeAPI{
+		t:               t,
+		nextID:          1,
+		version:         "1.2.0",
+		resumes:         map[int64]map[string]interface{}{},
+		versions:        map[int64]int{},
+		gone:            map[int64]bool{},
+		idempotencyKeys: map[string]fakeIdempotentCreate{},
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(f.Close)
+
+	return f
+}
+
+// client returns a client for the fake API which retries without waiting.
+func (f *fakeAPI) client(opts ...option) *client {
+	c := newClient(f.URL, fakeAPIToken, append([]option{withRetry(3, 10*time.Millisecond)}, opts...)...)
+	c.retryMinWait = time.Millisecond
+	return c
+}
+
+// Requests returns "<method> <path>" for every request received so far.
+func (f *fakeAPI) Requests() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.requests...)
+}
+
+// Header returns the header of the last request.
+func (f *fakeAPI) Header() http.Header {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.header
+}
+
+// Resumes returns the number of stored resumes.
+func (f *fakeAPI) Resumes() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.resumes)
+}
+
+func (f *fakeAPI) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if f.stalled.Load() {
+		<-r.Context().Done()
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.t.Log("Received " + r.Method + " " + r.RequestURI)
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	f.header = r.Header.Clone()
+
+	if r.Header.Get("Authorization") != "Bearer "+fakeAPIToken {
+		f.respond(w, http.StatusUnauthorized, map[string]interface{}{"error": "Unauthorized"})
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/info":
+		f.respond(w, http.StatusOK, map[string]interface{}{
+			"name":        "Resume API",
+			"version":     f.version,
+			"environment": "development",
+		})
+	case r.Method == http.MethodGet && r.URL.Path == "/resumes":
+		f.list(w, r)
+	case r.Method == http.MethodPost && r.URL.Path == "/resumes":
+		f.create(w, r)
+	case len(segments) == 2 && segments[0] == "resumes":
+		id, err := strconv.ParseInt(segments[1], 10, 64)
+		resume, ok := f.resumes[id]
+		if f.gone[id] {
+			f.respond(w, http.StatusGone, map[string]interface{}{"status": 410, "error": "Gone"})
+			return
+		}
+		if err != nil || !ok {
+			f.respond(w, http.StatusNotFound, map[string]interface{}{"status": 404, "error": "Not Found"})
+			return
+		}
+
+		// If-Match uses the strong comparison, weak ETags never match.
+		ifMatch := r.Header.Get("If-Match")
+		if ifMatch != "" && (strings.HasPrefix(ifMatch, "W/") || ifMatch != f.etag(id)) {
+			f.respond(w, http.StatusPreconditionFailed, map[string]interface{}{"status": 412, "error": "Precondition Failed"})
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			if etag := f.etag(id); r.Header.Get("If-None-Match") == etag {
+				w.Header().Set("ETag", etag)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			f.respondResume(w, http.StatusOK, resume)
+		case http.MethodPatch:
+			f.update(w, r, resume)
+		case http.MethodDelete:
+			delete(f.resumes, id)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			f.respond(w, http.StatusMethodNotAllowed, map[string]interface{}{"error": "Method Not Allowed"})
+		}
+	default:
+		f.t.Error("Unexpected " + r.Method + " " + r.RequestURI)
+		f.respond(w, http.StatusNotFound, map[string]interface{}{"status": 404, "error": "Not Found"})
+	}
+}
+
+func (f *fakeAPI) list(w http.ResponseWriter, r *http.Request) {
+	if f.failLists > 0 {
+		f.failLists--
+		f.respond(w, http.StatusServiceUnavailable, map[string]interface{}{"error": "Service Unavailable"})
+		return
+	}
+
+	ids := make([]int64, 0, len(f.resumes))
+	if key := r.URL.Query().Get("idempotency_key"); key != "" {
+		if create, ok := f.idempotencyKeys[key]; ok {
+			if _, ok := f.resumes[create.id]; ok {
+				ids = append(ids, create.id)
+			}
+		}
+	} else {
+		for id := range f.resumes {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	resumes := make([]map[string]interface{}, 0, len(ids))
+	for _, id := range ids {
+		resumes = append(resumes, f.resumes[id])
+	}
+	f.respond(w, http.StatusOK, resumes)
+}
+
+func (f *fakeAPI) create(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("Idempotency-Key")
+	if key == "" {
+		f.respond(w, http.StatusBadRequest, map[string]interface{}{"error": "Idempotency-Key header is missing"})
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		f.t.Error(err)
+		return
+	}
+
+	if create, ok := f.idempotencyKeys[key]; ok {
+		if create.body != string(body) {
+			f.respond(w, http.StatusUnprocessableEntity, map[string][]string{
+				"idempotency_key": {"has already been used for a different request"},
+			})
+			return
+		}
+
+		// Replay the response of the original request.
+		w.Header().Set("Idempotent-Replayed", "true")
+		f.created(w, f.resumes[create.id])
+		return
+	}
+
+	resume := map[string]interface{}{}
+	if err := json.Unmarshal(body, &resume); err != nil {
+		f.respond(w, http.StatusBadRequest, map[string]interface{}{"error": err.Error()})
+		return
+	}
+	if !f.valid(w, resume) {
+		return
+	}
+	f.normalize(resume)
+
+	id := f.nextID
+	f.nextID++
+	resume["id"] = id
+	f.resumes[id] = resume
+	f.versions[id] = 1
+	f.idempotencyKeys[key] = fakeIdempotentCreate{body: string(body), id: id}
+	if f.stallCreateResponses {
+		// Serve other requests in the meantime.
+		f.mu.Unlock()
+		<-r.Context().Done()
+		f.mu.Lock()
+		return
+	}
+	f.created(w, resume)
+}
+
+func (f *fakeAPI) created(w http.ResponseWriter, resume map[string]interface{}) {
+	if f.dropCreateResponses > 0 {
+		f.dropCreateResponses--
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			f.t.Error(err)
+			return
+		}
+		_ = conn.Close()
+		return
+	}
+
+	f.respondResume(w, http.StatusCreated, resume)
+}
+
+func (f *fakeAPI) update(w http.ResponseWriter, r *http.Request, resume map[string]interface{}) {
+	patch := map[string]interface{}{}
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		f.respond(w, http.StatusBadRequest, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	updated := map[string]interface{}{}
+	for key, value := range resume {
+		updated[key] = value
+	}
+	for key, value := range patch {
+		if key != "id" {
+			updated[key] = value
+		}
+	}
+	if !f.valid(w, updated) {
+		return
+	}
+	f.normalize(updated)
+
+	for key, value := range updated {
+		resume[key] = value
+	}
+	f.versions[resume["id"].(int64)]++
+	f.respondResume(w, http.StatusOK, resume)
+}
+
+// valid renders Rails validation errors for resume, if any.
+func (f *fakeAPI) valid(w http.ResponseWriter, resume map[string]interface{}) bool {
+	if name, _ := resume["name"].(string); name == "" {
+		f.respond(w, http.StatusUnprocessableEntity, map[string][]string{"name": {"can't be blank"}})
+		return false
+	}
+	return true
+}
+
+// normalize normalizes the URLs of resume if normalizeURLs is set.
+func (f *fakeAPI) normalize(resume map[string]interface{}) {
+	if !f.normalizeURLs {
+		return
+	}
+
+	normalize := func(value interface{}) interface{} {
+		u, err := url.Parse(fmt.Sprint(value))
+		if value == nil || err != nil {
+			return value
+		}
+		u.Scheme, u.Host, u.Path = strings.ToLower(u.Scheme), strings.ToLower(u.Host), strings.TrimRight(u.Path, "/")
+		return u.String()
+	}
+
+	if website, ok := resume["website"]; ok {
+		resume["website"] = normalize(website)
+	}
+	profiles, _ := resume["profiles"].([]interface{})
+	for _, profile := range profiles {
+		if profile, ok := profile.(map[string]interface{}); ok && profile["url"] != nil {
+			profile["url"] = normalize(profile["url"])
+		}
+	}
+}
+
+// change sets field of the resume with id like an edit in the web UI would.
+func (f *fakeAPI) change(id int64, field string, value interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.resumes[id][field] = value
+	f.versions[id]++
+}
+
+// field returns the stored value of field of the resume with id, ok is false
+// if it was never set.
+func (f *fakeAPI) field(id int64, field string) (value interface{}, ok bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	value, ok = f.resumes[id][field]
+	return value, ok
+}
+
+// remove deletes the resume with id like the web UI would, either purging it
+// or leaving no trace of it.
+func (f *fakeAPI) remove(id int64, purged bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.resumes, id)
+	f.gone[id] = purged
+}
+
+// etag returns the ETag of the current version of the resume with id.
+func (f *fakeAPI) etag(id int64) string {
+	etag := fmt.Sprintf(`"%d-%d"`, id, f.versions[id])
+	if f.weakETags {
+		return "W/" + etag
+	}
+	return etag
+}
+
+func (f *fakeAPI) respondResume(w http.ResponseWriter, status int, resume map[string]interface{}) {
+	w.Header().Set("ETag", f.etag(resume["id"].(int64)))
+	f.respond(w, status, resume)
+}
+
+func (f *fakeAPI) respond(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", fmt.Sprintf("fake-%d", len(f.requests)))
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		f.t.Error(err)
+	}
+}
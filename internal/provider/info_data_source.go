@@ -2,13 +2,11 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"io"
-	"net/http"
+	"github.com/lagerfeuer/terraform-provider-resume/internal/resumeapi"
 )
 
 var (
@@ -21,7 +19,7 @@ func NewInfoDataSource() datasource.DataSource {
 }
 
 type infoDataSource struct {
-	client *client
+	api *resumeapi.Client
 }
 
 type infoDataSourceModel struct {
@@ -31,12 +29,6 @@ type infoDataSourceModel struct {
 	Environment types.String `tfsdk:"environment"`
 }
 
-type infoDataSourceJson struct {
-	Name        string `json:"name"`
-	Version     string `json:"version"`
-	Environment string `json:"environment"`
-}
-
 func (d *infoDataSource) Configure(
 	_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse,
 ) {
@@ -44,17 +36,18 @@ func (d *infoDataSource) Configure(
 		return
 	}
 
-	client, ok := req.ProviderData.(*client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
 			fmt.Sprintf(
-				"Expected *client, got: %T. Please report this to the provider developer.",
+				"Expected *providerData, got: %T. Please report this to the provider developer.",
 				req.ProviderData,
 			),
 		)
+		return
 	}
-	d.client = client
+	d.api = resumeapi.New(data.client)
 }
 
 func (d *infoDataSource) Metadata(
@@ -88,41 +81,14 @@ func (d *infoDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, r
 func (d *infoDataSource) Read(
 	ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse,
 ) {
-	var state infoDataSourceModel
-
-	httpResp, err := d.client.Get(ctx, "/info")
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to read /info",
-			err.Error(),
-		)
-		return
-	}
+	ctx, span := startSpan(ctx, "resume_info.Read")
+	defer func() { endSpan(span, resp.Diagnostics) }()
 
-	if httpResp.StatusCode != http.StatusOK {
-		resp.Diagnostics.AddError(
-			"HTTP Error",
-			fmt.Sprintf("Expected HTTP status code %d, but got: %d", http.StatusOK, httpResp.StatusCode),
-		)
-		return
-	}
-
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to read HTTP response body",
-			err.Error(),
-		)
-		return
-	}
+	var state infoDataSourceModel
 
-	var data infoDataSourceJson
-	err = json.Unmarshal(body, &data)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to parse HTTP response body",
-			err.Error(),
-		)
+	data, _, diags := d.api.GetInfo(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
@@ -134,7 +100,7 @@ func (d *infoDataSource) Read(
 	state.Version = types.StringValue(data.Version)
 	state.Environment = types.StringValue(data.Environment)
 
-	diags := resp.State.Set(ctx, &state)
+	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
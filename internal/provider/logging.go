@@ -0,0 +1,190 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// httpLogSubsystem is the tflog subsystem of the API client. Its level can be
+// set separately through TF_LOG_PROVIDER_RESUME_HTTP.
+const httpLogSubsystem = "resume_http"
+
+// maxLoggedBodySize caps how much of a response body is kept for logging.
+const maxLoggedBodySize = 64 << 10
+
+// piiFields are the attributes of a resume which identify a person and must
+// never show up in logs.
+var piiFields = []string{"name", "address", "phone_number", "website", "emails", "profiles"}
+
+// piiPattern matches PII fields, including their value, in a JSON document.
+var piiPattern = regexp.MustCompile(
+	`"(?:` + strings.Join(piiFields, "|") + `)"\s*:\s*(?:"(?:[^"\\]|\\.)*"|\{[^{}]*\}|\[[^\[\]]*\])`,
+)
+
+// withHTTPLogging returns ctx with the logger of httpLogSubsystem, which masks
+// the Authorization header, the values of secretHeaders and PII fields.
+func withHTTPLogging(ctx context.Context, secretHeaders http.Header) context.Context {
+	maskedKeys := append([]string{headerLogKey("Authorization")}, piiFields...)
+	for key := range secretHeaders {
+		maskedKeys = append(maskedKeys, headerLogKey(key))
+	}
+
+	ctx = tflog.NewSubsystem(ctx, httpLogSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_RESUME", "HTTP"))
+	ctx = tflog.SubsystemMaskFieldValuesWithFieldKeys(ctx, httpLogSubsystem, maskedKeys...)
+	ctx = tflog.SubsystemMaskAllFieldValuesRegexes(ctx, httpLogSubsystem, piiPattern)
+	ctx = tflog.SubsystemMaskMessageRegexes(ctx, httpLogSubsystem, piiPattern)
+	return ctx
+}
+
+// traceHTTPBodies reports whether TRACE logging is enabled for
+// httpLogSubsystem. The provider logs everything and Terraform filters by
+// level, so the level is taken from the environment: the first variable set
+// of TF_LOG_PROVIDER_RESUME_HTTP, TF_LOG_PROVIDER_RESUME, TF_LOG_PROVIDER and
+// TF_LOG.
+func traceHTTPBodies() bool {
+	for _, key := range []string{"TF_LOG_PROVIDER_RESUME_HTTP", "TF_LOG_PROVIDER_RESUME", "TF_LOG_PROVIDER", "TF_LOG"} {
+		if level := strings.ToUpper(strings.TrimSpace(os.Getenv(key))); level != "" {
+			// Terraform logs JSON at TRACE.
+			return level == "TRACE" || level == "JSON"
+		}
+	}
+	return false
+}
+
+// logRequest logs the headers and body of req at TRACE.
+func logRequest(ctx context.Context, req *http.Request, payload []byte, attempt int) {
+	fields := map[string]interface{}{
+		"method":  req.Method,
+		"path":    req.URL.Path,
+		"attempt": attempt,
+	}
+	for key := range req.Header {
+		fields[headerLogKey(key)] = req.Header.Get(key)
+	}
+	if len(payload) > 0 {
+		fields["request_body"] = string(payload)
+	}
+
+	tflog.SubsystemTrace(ctx, httpLogSubsystem, "Sending HTTP request", fields)
+}
+
+// logResponse logs the outcome of req at DEBUG and the buffered response
+// body, if any, at TRACE.
+func logResponse(
+	ctx context.Context, req *http.Request, resp *http.Response, body []byte, err error, latency time.Duration,
+	attempt int,
+) {
+	fields := map[string]interface{}{
+		"method":     req.Method,
+		"path":       req.URL.Path,
+		"attempt":    attempt,
+		"latency_ms": latency.Milliseconds(),
+	}
+
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemDebug(ctx, httpLogSubsystem, "HTTP request failed", fields)
+		return
+	}
+
+	fields["status"] = resp.StatusCode
+	if requestID := resp.Header.Get("X-Request-Id"); requestID != "" {
+		fields["request_id"] = requestID
+	}
+	tflog.SubsystemDebug(ctx, httpLogSubsystem, "Received HTTP response", fields)
+
+	if len(body) > maxLoggedBodySize {
+		logResponseBody(ctx, req, body[:maxLoggedBodySize], true)
+	} else {
+		logResponseBody(ctx, req, body, false)
+	}
+}
+
+// logResponseBody logs the body of the response to req at TRACE. truncated
+// tells that body is only the beginning of it.
+func logResponseBody(ctx context.Context, req *http.Request, body []byte, truncated bool) {
+	if len(body) == 0 {
+		return
+	}
+
+	fields := map[string]interface{}{
+		"method":        req.Method,
+		"path":          req.URL.Path,
+		"response_body": string(body),
+	}
+	if truncated {
+		fields["response_body_truncated"] = true
+	}
+	tflog.SubsystemTrace(ctx, httpLogSubsystem, "Received HTTP response body", fields)
+}
+
+// bufferResponseBody reads and closes the body of resp and replaces it with
+// the bytes read, so it can be logged and read again.
+func bufferResponseBody(resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// loggedBody passes a response body on to the caller and logs up to
+// maxLoggedBodySize of what was read once it is read to the end or closed,
+// whichever comes first. Nothing is kept if log is nil. Closing it calls
+// done, which ends the attempt of the request.
+type loggedBody struct {
+	io.ReadCloser
+	log  func(body []byte, truncated bool)
+	done func()
+
+	read      bytes.Buffer
+	truncated bool
+	logged    bool
+}
+
+func (b *loggedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if b.log != nil {
+		kept := p[:n]
+		if room := maxLoggedBodySize - b.read.Len(); len(kept) > room {
+			kept = kept[:room]
+			b.truncated = true
+		}
+		b.read.Write(kept)
+	}
+	if err == io.EOF {
+		b.flush()
+	}
+	return n, err
+}
+
+func (b *loggedBody) Close() error {
+	b.flush()
+	err := b.ReadCloser.Close()
+	b.done()
+	return err
+}
+
+func (b *loggedBody) flush() {
+	if !b.logged && b.log != nil {
+		b.logged = true
+		b.log(b.read.Bytes(), b.truncated)
+	}
+}
+
+// headerLogKey returns the log field key of an HTTP header, e.g.
+// request_header_content_type.
+func headerLogKey(header string) string {
+	return "request_header_" + strings.ReplaceAll(strings.ToLower(header), "-", "_")
+}
@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientLogging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-123")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"id":1,"name":"Jane Doe","address":{"street_lines":["1 Main St"],"city":"Springfield"},"phone_number":"+1 555 0100","website":null}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("TF_LOG_PROVIDER_RESUME_HTTP", "TRACE")
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	c := newClient(server.URL, "secret-token", withHeaders(map[string]string{"X-Api-Gateway-Key": "gateway-secret"}))
+	body := strings.NewReader(`{"name":"Jane Doe","address":{"street_lines":["1 Main St"],"city":"Springfield"},"phone_number":"+1 555 0100"}`)
+	resp, err := c.Patch(ctx, "/resumes/1", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	// The caller still gets the complete response body.
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "Jane Doe") {
+		t.Errorf("expected response body to be passed on, got %s", data)
+	}
+
+	for _, secret := range []string{"secret-token", "gateway-secret", "Jane Doe", "1 Main St", "Springfield", "555 0100"} {
+		if strings.Contains(output.String(), secret) {
+			t.Errorf("expected %q to be masked in logs:\n%s", secret, output.String())
+		}
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var response, responseBody map[string]interface{}
+	for _, entry := range entries {
+		switch entry["@message"] {
+		case "Received HTTP response":
+			response = entry
+		case "Received HTTP response body":
+			responseBody = entry
+		}
+	}
+
+	if response == nil {
+		t.Fatalf("expected response to be logged, got %v", entries)
+	}
+	if response["@module"] != "provider."+httpLogSubsystem {
+		t.Errorf("expected module provider.%s, got %v", httpLogSubsystem, response["@module"])
+	}
+	if response["@level"] != "debug" {
+		t.Errorf("expected level debug, got %v", response["@level"])
+	}
+	expected := map[string]interface{}{
+		"method":     "PATCH",
+		"path":       "/resumes/1",
+		"status":     float64(200),
+		"request_id": "req-123",
+	}
+	for key, value := range expected {
+		if response[key] != value {
+			t.Errorf("expected %s %v, got %v", key, value, response[key])
+		}
+	}
+	if _, ok := response["latency_ms"]; !ok {
+		t.Error("expected latency to be logged")
+	}
+
+	if responseBody == nil {
+		t.Fatal("expected response body to be logged at trace")
+	}
+	if body := responseBody["response_body"].(string); !strings.Contains(body, `"id":1`) {
+		t.Errorf("expected non-PII fields to be kept, got %s", body)
+	}
+}
+
+func TestTraceHTTPBodies(t *testing.T) {
+	cases := map[string]struct {
+		env      map[string]string
+		expected bool
+	}{
+		"unset":              {expected: false},
+		"debug":              {env: map[string]string{"TF_LOG": "DEBUG"}, expected: false},
+		"trace":              {env: map[string]string{"TF_LOG": "trace"}, expected: true},
+		"json":               {env: map[string]string{"TF_LOG": "JSON"}, expected: true},
+		"provider overrides": {env: map[string]string{"TF_LOG": "TRACE", "TF_LOG_PROVIDER": "INFO"}, expected: false},
+		"subsystem":          {env: map[string]string{"TF_LOG_PROVIDER_RESUME": "INFO", "TF_LOG_PROVIDER_RESUME_HTTP": "TRACE"}, expected: true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			for _, key := range []string{"TF_LOG_PROVIDER_RESUME_HTTP", "TF_LOG_PROVIDER_RESUME", "TF_LOG_PROVIDER", "TF_LOG"} {
+				t.Setenv(key, c.env[key])
+			}
+
+			if actual := traceHTTPBodies(); actual != c.expected {
+				t.Errorf("expected %t, got %t", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestLoggedBody(t *testing.T) {
+	payload := strings.Repeat("x", maxLoggedBodySize+10)
+
+	var logged []byte
+	var truncated bool
+	body := &loggedBody{
+		ReadCloser: io.NopCloser(strings.NewReader(payload)),
+		log:        func(b []byte, t bool) { logged, truncated = append([]byte(nil), b...), t },
+		done:       func() {},
+	}
+	if data, err := io.ReadAll(body); err != nil || string(data) != payload {
+		t.Fatalf("expected the complete body to be passed on, got %d bytes, %v", len(data), err)
+	}
+	if len(logged) != maxLoggedBodySize || !truncated {
+		t.Errorf("expected %d truncated bytes to be logged, got %d, truncated %t", maxLoggedBodySize, len(logged), truncated)
+	}
+
+	untraced := &loggedBody{ReadCloser: io.NopCloser(strings.NewReader(payload)), done: func() {}}
+	if _, err := io.ReadAll(untraced); err != nil {
+		t.Fatal(err)
+	}
+	if err := untraced.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if untraced.read.Len() != 0 {
+		t.Errorf("expected nothing to be kept without a log, got %d bytes", untraced.read.Len())
+	}
+}
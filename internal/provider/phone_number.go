@@ -0,0 +1,198 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/nyaruka/phonenumbers"
+	"strings"
+)
+
+var (
+	_ basetypes.StringTypable                    = PhoneNumberType{}
+	_ basetypes.StringValuableWithSemanticEquals = PhoneNumber{}
+	_ validator.String                           = phoneNumberValidator{}
+)
+
+// unknownRegion makes phonenumbers.Parse accept international numbers only.
+const unknownRegion = "ZZ"
+
+// PhoneNumberType is a string holding a phone number. Its values are equal
+// if they refer to the same E.164 number, however they are formatted.
+type PhoneNumberType struct {
+	basetypes.StringType
+}
+
+func (t PhoneNumberType) String() string {
+	return "PhoneNumberType"
+}
+
+func (t PhoneNumberType) Equal(o attr.Type) bool {
+	other, ok := o.(PhoneNumberType)
+	return ok && t.StringType.Equal(other.StringType)
+}
+
+func (t PhoneNumberType) ValueType(ctx context.Context) attr.Value {
+	return PhoneNumber{}
+}
+
+func (t PhoneNumberType) ValueFromString(
+	ctx context.Context, in basetypes.StringValue,
+) (basetypes.StringValuable, diag.Diagnostics) {
+	return PhoneNumber{StringValue: in}, nil
+}
+
+func (t PhoneNumberType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	value, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := value.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", value)
+	}
+	return PhoneNumber{StringValue: stringValue}, nil
+}
+
+// PhoneNumber is a value of PhoneNumberType.
+type PhoneNumber struct {
+	basetypes.StringValue
+}
+
+func NewPhoneNumberNull() PhoneNumber {
+	return PhoneNumber{StringValue: basetypes.NewStringNull()}
+}
+
+func NewPhoneNumberUnknown() PhoneNumber {
+	return PhoneNumber{StringValue: basetypes.NewStringUnknown()}
+}
+
+func NewPhoneNumberValue(value string) PhoneNumber {
+	return PhoneNumber{StringValue: basetypes.NewStringValue(value)}
+}
+
+// NewPhoneNumberPointerValue returns a null phone number if value is nil.
+func NewPhoneNumberPointerValue(value *string) PhoneNumber {
+	return PhoneNumber{StringValue: basetypes.NewStringPointerValue(value)}
+}
+
+func (v PhoneNumber) Type(ctx context.Context) attr.Type {
+	return PhoneNumberType{}
+}
+
+func (v PhoneNumber) Equal(o attr.Value) bool {
+	other, ok := o.(PhoneNumber)
+	return ok && v.StringValue.Equal(other.StringValue)
+}
+
+// StringSemanticEquals reports whether v and the phone number in o refer to
+// the same number. The semantic equality of the framework does not know the
+// default region, a number in national format is therefore taken to be from
+// the country of the other number.
+func (v PhoneNumber) StringSemanticEquals(
+	ctx context.Context, o basetypes.StringValuable,
+) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	other, ok := o.(PhoneNumber)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T, got %T. Please report this to the provider developer.", v, o),
+		)
+		return false, diags
+	}
+
+	return samePhoneNumber(v.ValueString(), other.ValueString()), diags
+}
+
+// samePhoneNumber reports whether a and b refer to the same number.
+func samePhoneNumber(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if a == "" || b == "" {
+		return false
+	}
+
+	numberA, errA := phonenumbers.Parse(a, unknownRegion)
+	numberB, errB := phonenumbers.Parse(b, unknownRegion)
+	switch {
+	case errA == nil && errB == nil:
+		return phonenumbers.Format(numberA, phonenumbers.E164) == phonenumbers.Format(numberB, phonenumbers.E164)
+	case errA == nil:
+		e164, err := phoneNumberE164(b, phonenumbers.GetRegionCodeForCountryCode(int(numberA.GetCountryCode())))
+		return err == nil && e164 == phonenumbers.Format(numberA, phonenumbers.E164)
+	case errB == nil:
+		e164, err := phoneNumberE164(a, phonenumbers.GetRegionCodeForCountryCode(int(numberB.GetCountryCode())))
+		return err == nil && e164 == phonenumbers.Format(numberB, phonenumbers.E164)
+	}
+	return phonenumbers.NormalizeDigitsOnly(a) == phonenumbers.NormalizeDigitsOnly(b)
+}
+
+// errPhoneNumberRegion is returned for numbers in national format without a
+// default region.
+var errPhoneNumberRegion = errors.New("the number is not in international format and no default region is set")
+
+// phoneNumberE164 returns value in E.164 format. Numbers in national format
+// are taken to be from defaultRegion, an ISO 3166-1 alpha-2 code.
+func phoneNumberE164(value, defaultRegion string) (string, error) {
+	if defaultRegion == "" {
+		defaultRegion = unknownRegion
+	}
+
+	number, err := phonenumbers.Parse(value, defaultRegion)
+	if errors.Is(err, phonenumbers.ErrInvalidCountryCode) && !strings.HasPrefix(strings.TrimSpace(value), "+") &&
+		defaultRegion == unknownRegion {
+		return "", errPhoneNumberRegion
+	}
+	if err != nil {
+		return "", err
+	}
+	if !phonenumbers.IsPossibleNumber(number) {
+		return "", errors.New("the number has an impossible length for its country")
+	}
+	return phonenumbers.Format(number, phonenumbers.E164), nil
+}
+
+// validRegion reports whether region is a region with a numbering plan.
+func validRegion(region string) bool {
+	_, ok := phonenumbers.GetSupportedRegions()[region]
+	return ok
+}
+
+// phoneNumberValidator validates phone numbers as far as possible without
+// the default region of the provider, numbers in national format are
+// validated while planning.
+type phoneNumberValidator struct{}
+
+func (v phoneNumberValidator) Description(ctx context.Context) string {
+	return "value must be a phone number"
+}
+
+func (v phoneNumberValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v phoneNumberValidator) ValidateString(
+	ctx context.Context, req validator.StringRequest, resp *validator.StringResponse,
+) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() || req.ConfigValue.ValueString() == "" {
+		return
+	}
+
+	_, err := phoneNumberE164(req.ConfigValue.ValueString(), "")
+	if err != nil && !errors.Is(err, errPhoneNumberRegion) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Phone Number",
+			fmt.Sprintf("%q is not a valid phone number: %v.", req.ConfigValue.ValueString(), err),
+		)
+	}
+}
@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"testing"
+)
+
+func TestPhoneNumberE164(t *testing.T) {
+	cases := []struct {
+		value, region string
+		expected      string
+		err           bool
+	}{
+		{value: "+1 555 555 5555", expected: "+15555555555"},
+		{value: "+1 555 555 5555", region: "GB", expected: "+15555555555"},
+		{value: "+44 20 7946 0000", expected: "+442079460000"},
+		{value: "555-555-5555", region: "US", expected: "+15555555555"},
+		{value: "(555) 555-5555", region: "US", expected: "+15555555555"},
+		{value: "020 7946 0000", region: "GB", expected: "+442079460000"},
+		{value: "555-555-5555", err: true},
+		{value: "+1 555", err: true},
+		{value: "+999 555 555 5555", err: true},
+		{value: "call me", region: "US", err: true},
+	}
+
+	for _, c := range cases {
+		e164, err := phoneNumberE164(c.value, c.region)
+		if c.err {
+			if err == nil {
+				t.Errorf("expected %q in %q to be rejected, got %q", c.value, c.region, e164)
+			}
+			continue
+		}
+		if err != nil || e164 != c.expected {
+			t.Errorf("expected %q in %q to be %q, got %q, %v", c.value, c.region, c.expected, e164, err)
+		}
+	}
+
+	if _, err := phoneNumberE164("555-555-5555", ""); !errors.Is(err, errPhoneNumberRegion) {
+		t.Errorf("expected missing default region error, got %v", err)
+	}
+}
+
+func TestPhoneNumberSemanticEquals(t *testing.T) {
+	cases := []struct {
+		a, b  string
+		equal bool
+	}{
+		{a: "+1 555 555 5555", b: "+1 555 555 5555", equal: true},
+		{a: "+1 555 555 5555", b: "+15555555555", equal: true},
+		{a: "555-555-5555", b: "+1 (555) 555-5555", equal: true},
+		{a: "+15555555555", b: "(555) 555-5555", equal: true},
+		{a: "020 7946 0000", b: "+44 20 7946 0000", equal: true},
+		{a: "555-555-5555", b: "555.555.5555", equal: true},
+		{a: "+1 555 555 5555", b: "+1 555 555 5556"},
+		{a: "+1 555 555 5555", b: "+44 555 555 5555"},
+		{a: "555-555-5555", b: "+44 20 7946 0000"},
+		{a: "555-555-5555", b: "555-555-5556"},
+		{a: "555-555-5555", b: ""},
+	}
+
+	ctx := context.Background()
+	for _, c := range cases {
+		equal, diags := NewPhoneNumberValue(c.a).StringSemanticEquals(ctx, NewPhoneNumberValue(c.b))
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		if equal != c.equal {
+			t.Errorf("expected %q and %q to be equal %t, got %t", c.a, c.b, c.equal, equal)
+		}
+	}
+}
+
+func TestPhoneNumberValidator(t *testing.T) {
+	cases := map[string]bool{
+		"+1 555 555 5555": true,
+		// National numbers are validated while planning.
+		"555-555-5555": true,
+		"":             true,
+		"+1 555":       false,
+		"call me":      false,
+	}
+
+	for value, valid := range cases {
+		req := validator.StringRequest{Path: path.Root("phone_number"), ConfigValue: types.StringValue(value)}
+		var resp validator.StringResponse
+		phoneNumberValidator{}.ValidateString(context.Background(), req, &resp)
+
+		if resp.Diagnostics.HasError() == valid {
+			t.Errorf("expected %q to be valid %t, got %v", value, valid, resp.Diagnostics)
+		}
+	}
+}
+
+func TestResumeResourcePhoneNumber(t *testing.T) {
+	api := newFakeAPI(t)
+	config := api.config()
+	config.DefaultRegion = types.StringValue("us")
+	p := api.provider(config)
+
+	resume := testResumePlan("Michael G Scott")
+	resume.Id = types.StringNull()
+	resume.PhoneNumber = NewPhoneNumberValue("555-555-5555")
+	resume.PhoneNumberE164 = types.StringNull()
+
+	plan, diags := p.planResume(nil, &resume)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if plan.PhoneNumberE164.ValueString() != "+15555555555" {
+		t.Errorf("expected planned E.164 number, got %s", plan.PhoneNumberE164)
+	}
+
+	created, private, diags := p.applyResume(nil, &plan, nil)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	// The API normalizing the number is no change.
+	api.change(1, "phone_number", "+1 (555) 555-5555")
+	refreshed, _, diags := p.readResume(created, private)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if refreshed.PhoneNumber.ValueString() != "555-555-5555" || refreshed.PhoneNumberE164.ValueString() != "+15555555555" {
+		t.Errorf("expected the configured number to be kept, got %s, %s", refreshed.PhoneNumber, refreshed.PhoneNumberE164)
+	}
+
+	api.change(1, "phone_number", "+1 555 555 5556")
+	refreshed, _, diags = p.readResume(created, private)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if refreshed.PhoneNumber.ValueString() != "+1 555 555 5556" || refreshed.PhoneNumberE164.ValueString() != "+15555555556" {
+		t.Errorf("expected the changed number, got %s, %s", refreshed.PhoneNumber, refreshed.PhoneNumberE164)
+	}
+}
+
+func TestResumeResourcePhoneNumberRegion(t *testing.T) {
+	api := newFakeAPI(t)
+	p := api.provider(api.config())
+
+	resume := testResumePlan("Michael G Scott")
+	resume.Id = types.StringNull()
+	resume.PhoneNumber = NewPhoneNumberValue("555-555-5555")
+	resume.PhoneNumberE164 = types.StringNull()
+
+	_, diags := p.planResume(nil, &resume)
+	if errs := diags.Errors(); len(errs) != 1 || errs[0].Summary() != "Invalid Phone Number" {
+		t.Fatalf("expected an Invalid Phone Number error, got %v", diags)
+	}
+
+	resume.PhoneNumber = NewPhoneNumberValue("+1 555 555 5555")
+	plan, diags := p.planResume(nil, &resume)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if plan.PhoneNumberE164.ValueString() != "+15555555555" {
+		t.Errorf("expected planned E.164 number, got %s", plan.PhoneNumberE164)
+	}
+}
+
+func TestProviderDefaultRegionInvalid(t *testing.T) {
+	api := newFakeAPI(t)
+	config := api.config()
+	config.DefaultRegion = types.StringValue("XX")
+
+	_, diags := newTestProvider(t, config)
+	if errs := diags.Errors(); len(errs) != 1 || errs[0].Summary() != "Invalid Default Region" {
+		t.Fatalf("expected an Invalid Default Region error, got %v", diags)
+	}
+}
@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/lagerfeuer/terraform-provider-resume/internal/resumeapi"
+	"net/http"
+)
+
+// minimumAPIVersion is the oldest Resume API version the provider supports.
+var minimumAPIVersion = version.Must(version.NewVersion("1.0.0"))
+
+// resumeAttributeVersions maps attributes of resume_resume to the Resume API
+// version which introduced them. Attributes which are not listed are
+// supported by minimumAPIVersion.
+var resumeAttributeVersions = map[string]*version.Version{
+	// 1.1.0 replaced the free-text address with a structured one.
+	"address":  version.Must(version.NewVersion("1.1.0")),
+	"emails":   version.Must(version.NewVersion("1.2.0")),
+	"profiles": version.Must(version.NewVersion("1.2.0")),
+}
+
+// preflight asks the API at c for its version, so a wrong endpoint, a
+// rejected token or an outdated API fail the provider configuration instead
+// of the first resource operation. The version is nil if the API reports
+// none the provider understands. An unreachable API is reported at
+// endpointPath, the attribute configuring the endpoint.
+func preflight(ctx context.Context, c *client, endpointPath path.Path) (*version.Version, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	info, status, infoDiags := resumeapi.New(c).GetInfo(ctx)
+	if infoDiags.HasError() {
+		switch status {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			diags.AddAttributeError(
+				path.Root("token"),
+				"Resume API Token Rejected",
+				fmt.Sprintf("The Resume API rejected the configured credentials: %s", errorDetail(infoDiags)),
+			)
+		case 0:
+			diags.AddAttributeError(
+				endpointPath,
+				"Resume API Unreachable",
+				fmt.Sprintf("The Resume API could not be reached: %s\n\n"+
+					"Set skip_preflight to configure the provider regardless, "+
+					"e.g. if the API is not reachable while planning.", errorDetail(infoDiags)),
+			)
+		default:
+			diags.AddError(
+				"Resume API Preflight Failed",
+				fmt.Sprintf("The Resume API could not be queried for its version: %s\n\n"+
+					"Set skip_preflight to configure the provider regardless.", errorDetail(infoDiags)),
+			)
+		}
+		return nil, diags
+	}
+
+	serverVersion, err := version.NewVersion(info.Version)
+	if err != nil {
+		diags.AddWarning(
+			"Unknown Resume API Version",
+			fmt.Sprintf("The Resume API reported version %q, which cannot be parsed: %v. "+
+				"Compatibility with the provider is not checked.", info.Version, err),
+		)
+		return nil, diags
+	}
+
+	if serverVersion.LessThan(minimumAPIVersion) {
+		diags.AddError(
+			"Unsupported Resume API Version",
+			fmt.Sprintf("The Resume API runs version %s, but the provider requires %s or later.",
+				serverVersion, minimumAPIVersion),
+		)
+		return nil, diags
+	}
+
+	tflog.Debug(ctx, "Resume API preflight succeeded", map[string]interface{}{
+		"api_version":     serverVersion.String(),
+		"api_environment": info.Environment,
+	})
+	return serverVersion, diags
+}
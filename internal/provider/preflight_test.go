@@ -0,0 +1,177 @@
+package provider
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"testing"
+)
+
+func TestProviderPreflight(t *testing.T) {
+	cases := map[string]struct {
+		version   string
+		configure func(api *fakeAPI, config *ResumeProviderModel)
+		expected  []diag.Diagnostic
+	}{
+		"supported": {
+			version: "1.2.0",
+		},
+		"token rejected": {
+			version: "1.0.0",
+			configure: func(api *fakeAPI, config *ResumeProviderModel) {
+				config.Token = types.StringValue("bar")
+			},
+			expected: []diag.Diagnostic{
+				diag.NewAttributeErrorDiagnostic(path.Root("token"), "Resume API Token Rejected", ""),
+			},
+		},
+		"unreachable": {
+			version: "1.0.0",
+			configure: func(api *fakeAPI, config *ResumeProviderModel) {
+				api.Close()
+				config.MaxRetries = types.Int64Value(0)
+			},
+			expected: []diag.Diagnostic{
+				diag.NewAttributeErrorDiagnostic(path.Root("endpoint"), "Resume API Unreachable", ""),
+			},
+		},
+		"unreachable endpoints": {
+			version: "1.0.0",
+			configure: func(api *fakeAPI, config *ResumeProviderModel) {
+				api.Close()
+				config.Endpoint = types.StringNull()
+				config.Endpoints = types.ListValueMust(types.StringType, []attr.Value{types.StringValue(api.URL)})
+				config.MaxRetries = types.Int64Value(0)
+			},
+			expected: []diag.Diagnostic{
+				diag.NewAttributeErrorDiagnostic(path.Root("endpoints"), "Resume API Unreachable", ""),
+			},
+		},
+		"skipped": {
+			version: "1.0.0",
+			configure: func(api *fakeAPI, config *ResumeProviderModel) {
+				api.Close()
+				config.SkipPreflight = types.BoolValue(true)
+			},
+		},
+		"unsupported version": {
+			version: "0.9.0",
+			expected: []diag.Diagnostic{
+				diag.NewErrorDiagnostic("Unsupported Resume API Version", ""),
+			},
+		},
+		"unknown version": {
+			version: "development",
+			expected: []diag.Diagnostic{
+				diag.NewWarningDiagnostic("Unknown Resume API Version", ""),
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			api.version = c.version
+			config := api.config()
+			if c.configure != nil {
+				c.configure(api, &config)
+			}
+
+			_, diags := newTestProvider(t, config)
+
+			if len(diags) != len(c.expected) {
+				t.Fatalf("expected %d diagnostics, got %v", len(c.expected), diags)
+			}
+			for i, e := range c.expected {
+				d := diags[i]
+				if d.Severity() != e.Severity() || d.Summary() != e.Summary() {
+					t.Errorf("expected diagnostic %d %q, got %q: %s", i, e.Summary(), d.Summary(), d.Detail())
+				}
+				var expectedPath, actualPath path.Path
+				if withPath, ok := e.(diag.DiagnosticWithPath); ok {
+					expectedPath = withPath.Path()
+				}
+				if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+					actualPath = withPath.Path()
+				}
+				if !actualPath.Equal(expectedPath) {
+					t.Errorf("expected diagnostic %d at %q, got %q", i, expectedPath, actualPath)
+				}
+			}
+		})
+	}
+}
+
+func TestResumeResourceUnsupportedAttribute(t *testing.T) {
+	plan := testResumePlan("Michael G Scott")
+	plan.Id = types.StringNull()
+	plan.PhoneNumberE164 = types.StringNull()
+	plan.Address = testAddress([]string{"1725 Slough Avenue"}, "Scranton", "PA", "18505", "US")
+	plan.Emails = []resumeEmailModel{testEmail("michael@dundermifflin.com", "", true)}
+	plan.Profiles = []resumeProfileModel{testProfile("GitHub", "mscott", "")}
+
+	unset := testResumePlan("Michael G Scott")
+	unset.Id = types.StringNull()
+	unset.PhoneNumberE164 = types.StringNull()
+
+	cases := map[string]struct {
+		version string
+		config  resumeResourceModel
+		// attributeVersions replaces resumeAttributeVersions if set.
+		attributeVersions map[string]*version.Version
+		expected          []path.Path
+	}{
+		"unsupported": {
+			version:  "1.0.0",
+			config:   plan,
+			expected: []path.Path{path.Root("address"), path.Root("emails"), path.Root("profiles")},
+		},
+		"partly supported": {
+			version:  "1.1.0",
+			config:   plan,
+			expected: []path.Path{path.Root("emails"), path.Root("profiles")},
+		},
+		"supported": {version: "1.2.0", config: plan},
+		"unset":     {version: "1.0.0", config: unset},
+		"injected": {
+			version:           "1.2.0",
+			config:            plan,
+			attributeVersions: map[string]*version.Version{"name": version.Must(version.NewVersion("2.0.0"))},
+			expected:          []path.Path{path.Root("name")},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			api.version = c.version
+			p, diags := newTestProviderWith(t, &ResumeProvider{
+				version:           "test",
+				attributeVersions: c.attributeVersions,
+			}, api.config())
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+
+			_, diags = p.planResume(nil, &c.config)
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+
+			warnings := diags.Warnings()
+			if len(warnings) != len(c.expected) {
+				t.Fatalf("expected %d warnings, got %v", len(c.expected), warnings)
+			}
+			for i, w := range warnings {
+				if w.Summary() != "Attribute Not Supported by Resume API" {
+					t.Errorf("unexpected warning %q: %s", w.Summary(), w.Detail())
+				}
+				if withPath, ok := w.(diag.DiagnosticWithPath); !ok || !withPath.Path().Equal(c.expected[i]) {
+					t.Errorf("expected warning at %s, got %v", c.expected[i], w)
+				}
+			}
+		})
+	}
+}
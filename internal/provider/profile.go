@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/lagerfeuer/terraform-provider-resume/internal/resumeapi"
+)
+
+// resumeProfileModel is an online profile like in basics.profiles of JSON
+// Resume.
+type resumeProfileModel struct {
+	Network  types.String `tfsdk:"network"`
+	Username types.String `tfsdk:"username"`
+	URL      URL          `tfsdk:"url"`
+}
+
+// resumeProfileAttributes are the attributes of the profiles of
+// resume_resume.
+func resumeProfileAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"network": schema.StringAttribute{
+			Description: "Name of the network, e.g. \"GitHub\"",
+			Required:    true,
+		},
+		"username": schema.StringAttribute{
+			Optional: true,
+		},
+		"url": schema.StringAttribute{
+			CustomType: URLType{},
+			Optional:   true,
+			Validators: []validator.String{
+				urlValidator{},
+			},
+		},
+	}
+}
+
+// profilesToAPI returns nil if profiles is.
+func profilesToAPI(profiles []resumeProfileModel) *[]resumeapi.Profile {
+	if profiles == nil {
+		return nil
+	}
+
+	result := make([]resumeapi.Profile, 0, len(profiles))
+	for _, profile := range profiles {
+		result = append(result, resumeapi.Profile{
+			Network:  profile.Network.ValueString(),
+			Username: profile.Username.ValueStringPointer(),
+			URL:      profile.URL.ValueStringPointer(),
+		})
+	}
+	return &result
+}
+
+func profilesFromAPI(profiles *[]resumeapi.Profile) []resumeProfileModel {
+	if profiles == nil {
+		return nil
+	}
+
+	result := make([]resumeProfileModel, 0, len(*profiles))
+	for _, profile := range *profiles {
+		result = append(result, resumeProfileModel{
+			Network:  types.StringValue(profile.Network),
+			Username: types.StringPointerValue(profile.Username),
+			URL:      NewURLPointerValue(profile.URL),
+		})
+	}
+	return result
+}
@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// testProfile returns a profile without a username or URL if they are empty.
+func testProfile(network, username, url string) resumeProfileModel {
+	profile := resumeProfileModel{
+		Network:  types.StringValue(network),
+		Username: types.StringNull(),
+		URL:      NewURLNull(),
+	}
+	if username != "" {
+		profile.Username = types.StringValue(username)
+	}
+	if url != "" {
+		profile.URL = NewURLValue(url)
+	}
+	return profile
+}
+
+// sortProfiles sorts profiles by network, sets are unordered.
+func sortProfiles(profiles []resumeProfileModel) []resumeProfileModel {
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Network.ValueString() < profiles[j].Network.ValueString()
+	})
+	return profiles
+}
+
+func TestResumeResourceProfiles(t *testing.T) {
+	api := newFakeAPI(t)
+	p := api.provider(api.config())
+
+	plan := testResumePlan("Michael G Scott")
+	plan.Profiles = []resumeProfileModel{
+		testProfile("GitHub", "mscott", "https://github.com/mscott"),
+		testProfile("LinkedIn", "", "https://www.linkedin.com/in/michael-scott"),
+		testProfile("Mastodon", "@mscott@dunder.social", ""),
+	}
+	created, private, diags := p.applyResume(nil, &plan, nil)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if !reflect.DeepEqual(sortProfiles(created.Profiles), plan.Profiles) {
+		t.Errorf("expected created profiles %+v, got %+v", plan.Profiles, created.Profiles)
+	}
+	expected := []interface{}{
+		map[string]interface{}{"network": "GitHub", "username": "mscott", "url": "https://github.com/mscott"},
+		map[string]interface{}{"network": "LinkedIn", "url": "https://www.linkedin.com/in/michael-scott"},
+		map[string]interface{}{"network": "Mastodon", "username": "@mscott@dunder.social"},
+	}
+	stored, _ := api.field(1, "profiles")
+	if profiles, ok := stored.([]interface{}); !ok || len(profiles) != 3 || !reflect.DeepEqual(
+		sortStoredProfiles(profiles), expected,
+	) {
+		t.Errorf("expected profiles to be stored as %v, got %v", expected, stored)
+	}
+
+	planned := created
+	planned.Profiles = []resumeProfileModel{}
+	updated, _, diags := p.applyResume(&created, &planned, private)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if updated.Profiles == nil || len(updated.Profiles) != 0 {
+		t.Errorf("expected no profiles, got %+v", updated.Profiles)
+	}
+	if stored, _ := api.field(1, "profiles"); !reflect.DeepEqual(stored, []interface{}{}) {
+		t.Errorf("expected profiles to be stored as an empty list, got %v", stored)
+	}
+}
+
+func sortStoredProfiles(profiles []interface{}) []interface{} {
+	sort.Slice(profiles, func(i, j int) bool {
+		network := func(k int) string {
+			profile, _ := profiles[k].(map[string]interface{})
+			value, _ := profile["network"].(string)
+			return value
+		}
+		return network(i) < network(j)
+	})
+	return profiles
+}
+
+func TestResumeResourceProfileURLDomain(t *testing.T) {
+	api := newFakeAPI(t)
+	config := api.config()
+	config.DeniedURLDomains = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("myspace.com")})
+	p := api.provider(config)
+
+	resume := testResumePlan("Michael G Scott")
+	resume.Id = types.StringNull()
+	resume.PhoneNumberE164 = types.StringNull()
+	resume.Profiles = []resumeProfileModel{
+		testProfile("GitHub", "mscott", "https://github.com/mscott"),
+		testProfile("MySpace", "", "https://myspace.com/mscott"),
+	}
+
+	_, diags := p.planResume(nil, &resume)
+	if errs := diags.Errors(); len(errs) != 1 || errs[0].Summary() != "URL Domain Not Allowed" {
+		t.Fatalf("expected an URL Domain Not Allowed error, got %v", diags)
+	}
+
+	resume.Profiles = resume.Profiles[:1]
+	if _, diags := p.planResume(nil, &resume); diags.HasError() {
+		t.Fatal(diags)
+	}
+}
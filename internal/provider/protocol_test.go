@@ -0,0 +1,308 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"reflect"
+	"testing"
+)
+
+// testProvider drives the provider through the plugin protocol the way
+// Terraform does, so private state and diagnostics are handled as in
+// production.
+type testProvider struct {
+	t      *testing.T
+	server tfprotov6.ProviderServer
+}
+
+// newTestProvider configures the provider with config and returns it along
+// with the diagnostics of its configuration.
+func newTestProvider(t *testing.T, config ResumeProviderModel) (*testProvider, diag.Diagnostics) {
+	t.Helper()
+	return newTestProviderWith(t, New("test")(), config)
+}
+
+// newTestProviderWith is newTestProvider for p.
+func newTestProviderWith(
+	t *testing.T, p provider.Provider, config ResumeProviderModel,
+) (*testProvider, diag.Diagnostics) {
+	t.Helper()
+	ctx := context.Background()
+
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+
+	server, err := providerserver.NewProtocol6WithError(p)()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Terraform fetches the schemas first, which registers the resource and
+	// data source type names.
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diags := testDiagnostics(schemas.Diagnostics); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	resp, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{
+		Config: testDynamicValue(t, tfsdk.State{Schema: schemaResp.Schema}, config),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testProvider{t: t, server: server}, testDiagnostics(resp.Diagnostics)
+}
+
+// config returns the configuration of a provider using f, which retries
+// without waiting.
+func (f *fakeAPI) config() ResumeProviderModel {
+	return ResumeProviderModel{
+		Endpoint:          types.StringValue(f.URL),
+		Endpoints:         types.ListNull(types.StringType),
+		Token:             types.StringValue(fakeAPIToken),
+		MaxRetries:        types.Int64Value(3),
+		RetryMaxWait:      types.StringValue("1ms"),
+		Headers:           types.MapNull(types.StringType),
+		AllowedURLDomains: types.ListNull(types.StringType),
+		DeniedURLDomains:  types.ListNull(types.StringType),
+	}
+}
+
+// provider returns a provider configured with config, see fakeAPI.config.
+func (f *fakeAPI) provider(config ResumeProviderModel) *testProvider {
+	f.t.Helper()
+
+	p, diags := newTestProvider(f.t, config)
+	if diags.HasError() {
+		f.t.Fatal(diags)
+	}
+	return p
+}
+
+// testResumeState returns an empty state of resume_resume.
+func testResumeState(t *testing.T) tfsdk.State {
+	t.Helper()
+
+	var resp fwresource.SchemaResponse
+	NewResumeResource().Schema(context.Background(), fwresource.SchemaRequest{}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+	return tfsdk.State{Schema: resp.Schema}
+}
+
+// testResumePlan returns the plan of a resume_resume with name, leaving every
+// optional attribute unset.
+func testResumePlan(name string) resumeResourceModel {
+	return resumeResourceModel{
+		Id:          types.StringUnknown(),
+		Name:        types.StringValue(name),
+		PhoneNumber: NewPhoneNumberNull(),
+		Website:     NewURLNull(),
+
+		PhoneNumberE164: types.StringUnknown(),
+		Timeouts:        testResumeTimeouts(nil),
+	}
+}
+
+// testResumeTimeouts returns a timeouts block with values keyed by operation,
+// it is null if values is.
+func testResumeTimeouts(values map[string]string) timeouts.Value {
+	attrTypes := map[string]attr.Type{
+		"create": types.StringType,
+		"read":   types.StringType,
+		"update": types.StringType,
+		"delete": types.StringType,
+	}
+	if values == nil {
+		return timeouts.Value{Object: types.ObjectNull(attrTypes)}
+	}
+
+	attrs := map[string]attr.Value{}
+	for name := range attrTypes {
+		attrs[name] = types.StringNull()
+		if value, ok := values[name]; ok {
+			attrs[name] = types.StringValue(value)
+		}
+	}
+	return timeouts.Value{Object: types.ObjectValueMust(attrTypes, attrs)}
+}
+
+// planResume plans a resume_resume with config like terraform plan. A nil
+// prior plans to create the resume.
+func (p *testProvider) planResume(prior, config *resumeResourceModel) (resumeResourceModel, diag.Diagnostics) {
+	p.t.Helper()
+	s := testResumeState(p.t)
+
+	resp, err := p.server.PlanResourceChange(context.Background(), &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         "resume_resume",
+		PriorState:       testDynamicValue(p.t, s, prior),
+		ProposedNewState: testDynamicValue(p.t, s, config),
+		Config:           testDynamicValue(p.t, s, config),
+	})
+	if err != nil {
+		p.t.Fatal(err)
+	}
+
+	var planned resumeResourceModel
+	testDecodeDynamicValue(p.t, s, resp.PlannedState, &planned)
+	return planned, testDiagnostics(resp.Diagnostics)
+}
+
+// applyResume creates, updates or deletes a resume_resume like terraform
+// apply. A nil prior creates the resume, a nil planned deletes it. The
+// returned state is zero if the resume was deleted.
+func (p *testProvider) applyResume(prior, planned *resumeResourceModel, private []byte) (
+	resumeResourceModel, []byte, diag.Diagnostics,
+) {
+	p.t.Helper()
+	s := testResumeState(p.t)
+
+	resp, err := p.server.ApplyResourceChange(context.Background(), &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:       "resume_resume",
+		PriorState:     testDynamicValue(p.t, s, prior),
+		PlannedState:   testDynamicValue(p.t, s, planned),
+		Config:         testDynamicValue(p.t, s, planned),
+		PlannedPrivate: private,
+	})
+	if err != nil {
+		p.t.Fatal(err)
+	}
+
+	var state resumeResourceModel
+	testDecodeDynamicValue(p.t, s, resp.NewState, &state)
+	return state, resp.Private, testDiagnostics(resp.Diagnostics)
+}
+
+// readResume refreshes a resume_resume like terraform plan. The returned
+// state is zero if the resume was removed.
+func (p *testProvider) readResume(state resumeResourceModel, private []byte) (
+	resumeResourceModel, []byte, diag.Diagnostics,
+) {
+	p.t.Helper()
+	s := testResumeState(p.t)
+
+	resp, err := p.server.ReadResource(context.Background(), &tfprotov6.ReadResourceRequest{
+		TypeName:     "resume_resume",
+		CurrentState: testDynamicValue(p.t, s, &state),
+		Private:      private,
+	})
+	if err != nil {
+		p.t.Fatal(err)
+	}
+
+	var newState resumeResourceModel
+	testDecodeDynamicValue(p.t, s, resp.NewState, &newState)
+	return newState, resp.Private, testDiagnostics(resp.Diagnostics)
+}
+
+// upgradeResume upgrades the JSON state of a resume_resume of version like
+// Terraform does after the schema version changed.
+func (p *testProvider) upgradeResume(version int64, rawState string) (resumeResourceModel, diag.Diagnostics) {
+	p.t.Helper()
+
+	resp, err := p.server.UpgradeResourceState(context.Background(), &tfprotov6.UpgradeResourceStateRequest{
+		TypeName: "resume_resume",
+		Version:  version,
+		RawState: &tfprotov6.RawState{JSON: []byte(rawState)},
+	})
+	if err != nil {
+		p.t.Fatal(err)
+	}
+
+	var state resumeResourceModel
+	testDecodeDynamicValue(p.t, testResumeState(p.t), resp.UpgradedState, &state)
+	return state, testDiagnostics(resp.Diagnostics)
+}
+
+// testDynamicValue encodes value, a model of the schema of state, for the
+// plugin protocol. A nil value is encoded as null.
+func testDynamicValue(t *testing.T, state tfsdk.State, value interface{}) *tfprotov6.DynamicValue {
+	t.Helper()
+	ctx := context.Background()
+
+	typ := state.Schema.Type().TerraformType(ctx)
+	state.Raw = tftypes.NewValue(typ, nil)
+	if v := reflect.ValueOf(value); value != nil && !(v.Kind() == reflect.Pointer && v.IsNil()) {
+		if diags := state.Set(ctx, value); diags.HasError() {
+			t.Fatal(diags)
+		}
+	}
+
+	dv, err := tfprotov6.NewDynamicValue(typ, state.Raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &dv
+}
+
+// testDecodeDynamicValue decodes dv into target, a model of the schema of
+// state. target is left alone if dv is null.
+func testDecodeDynamicValue(t *testing.T, state tfsdk.State, dv *tfprotov6.DynamicValue, target interface{}) {
+	t.Helper()
+	ctx := context.Background()
+
+	if dv == nil {
+		return
+	}
+
+	raw, err := dv.Unmarshal(state.Schema.Type().TerraformType(ctx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw.IsNull() {
+		return
+	}
+
+	state.Raw = raw
+	if diags := state.Get(ctx, target); diags.HasError() {
+		t.Fatal(diags)
+	}
+}
+
+// testDiagnostics converts diagnostics of the plugin protocol back into
+// framework diagnostics.
+func testDiagnostics(protoDiags []*tfprotov6.Diagnostic) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, d := range protoDiags {
+		var p path.Path
+		if d.Attribute != nil {
+			for _, step := range d.Attribute.Steps() {
+				switch step := step.(type) {
+				case tftypes.AttributeName:
+					p = p.AtName(string(step))
+				case tftypes.ElementKeyInt:
+					p = p.AtListIndex(int(step))
+				case tftypes.ElementKeyString:
+					p = p.AtMapKey(string(step))
+				}
+			}
+		}
+
+		switch {
+		case d.Severity == tfprotov6.DiagnosticSeverityWarning && len(p.Steps()) > 0:
+			diags.AddAttributeWarning(p, d.Summary, d.Detail)
+		case d.Severity == tfprotov6.DiagnosticSeverityWarning:
+			diags.AddWarning(d.Summary, d.Detail)
+		case len(p.Steps()) > 0:
+			diags.AddAttributeError(p, d.Summary, d.Detail)
+		default:
+			diags.AddError(d.Summary, d.Detail)
+		}
+	}
+	return diags
+}
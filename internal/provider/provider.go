@@ -5,14 +5,22 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/net/http/httpguts"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -25,12 +33,67 @@ type ResumeProvider struct {
 	// provider is built and ran locally, and "test" when running acceptance
 	// testing.
 	version string
+
+	// wrapTransport wraps the transport of the client if set, tests use it
+	// to record and replay API interactions.
+	wrapTransport func(http.RoundTripper) http.RoundTripper
+	// attributeVersions replaces resumeAttributeVersions if set.
+	attributeVersions map[string]*version.Version
 }
 
 // ResumeProviderModel describes the provider data model.
 type ResumeProviderModel struct {
-	Endpoint types.String `tfsdk:"endpoint"`
-	Token    types.String `tfsdk:"token"`
+	Endpoint     types.String `tfsdk:"endpoint"`
+	Endpoints    types.List   `tfsdk:"endpoints"`
+	Token        types.String `tfsdk:"token"`
+	MaxRetries   types.Int64  `tfsdk:"max_retries"`
+	RetryMaxWait types.String `tfsdk:"retry_max_wait"`
+
+	RequestTimeout types.String `tfsdk:"request_timeout"`
+	Headers        types.Map    `tfsdk:"headers"`
+
+	RequestsPerSecond types.Float64 `tfsdk:"requests_per_second"`
+	RequestBurst      types.Int64   `tfsdk:"request_burst"`
+
+	CACertPEM          types.String `tfsdk:"ca_cert_pem"`
+	CACertFile         types.String `tfsdk:"ca_cert_file"`
+	ClientCertPEM      types.String `tfsdk:"client_cert_pem"`
+	ClientKeyPEM       types.String `tfsdk:"client_key_pem"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+	ProxyURL           types.String `tfsdk:"proxy_url"`
+
+	ConflictPolicy types.String `tfsdk:"conflict_policy"`
+	SkipPreflight  types.Bool   `tfsdk:"skip_preflight"`
+	DefaultRegion  types.String `tfsdk:"default_region"`
+
+	AllowedURLDomains types.List `tfsdk:"allowed_url_domains"`
+	DeniedURLDomains  types.List `tfsdk:"denied_url_domains"`
+
+	Auth *authModel `tfsdk:"auth"`
+}
+
+const (
+	// conflictPolicyFail fails updates and deletes of resumes changed
+	// outside of Terraform since they were last read.
+	conflictPolicyFail = "fail"
+	// conflictPolicyOverwrite replaces remote changes.
+	conflictPolicyOverwrite = "overwrite"
+)
+
+// providerData is handed to resources and data sources by Configure.
+type providerData struct {
+	client         *client
+	conflictPolicy string
+	// serverVersion is the version of the Resume API, nil if it is unknown
+	// because the preflight was skipped.
+	serverVersion *version.Version
+	// attributeVersions maps attributes of resume_resume to the Resume API
+	// version which introduced them, see resumeAttributeVersions.
+	attributeVersions map[string]*version.Version
+	// defaultRegion is the ISO 3166-1 alpha-2 code of the region of phone
+	// numbers in national format, empty if they are rejected.
+	defaultRegion string
+	urlDomains    urlDomains
 }
 
 func (p *ResumeProvider) Metadata(
@@ -46,14 +109,169 @@ func (p *ResumeProvider) Schema(
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"endpoint": schema.StringAttribute{
-				Description: "Resume API Endpoint",
-				Required:    true,
+				Description: "Resume API Endpoint, either an HTTP(S) URL or unix:///path/to/socket followed by " +
+					"an optional base path, conflicts with endpoints",
+				Optional: true,
+			},
+			"endpoints": schema.ListAttribute{
+				Description: "Resume API Endpoints of several instances of the API in order of preference, " +
+					"conflicts with endpoint. Requests stick to one endpoint and fail over to the next healthy " +
+					"one when it becomes unreachable.",
+				ElementType: types.StringType,
+				Optional:    true,
 			},
 			"token": schema.StringAttribute{
-				Description: "Resume API Token",
-				Required:    true,
+				Description: "Resume API Token, conflicts with the auth block",
+				Optional:    true,
 				Sensitive:   true,
 			},
+			"max_retries": schema.Int64Attribute{
+				Description: fmt.Sprintf(
+					"Maximum number of retries for failed requests, at most %d, defaults to %d",
+					maxRetriesLimit, defaultMaxRetries,
+				),
+				Optional: true,
+				Validators: []validator.Int64{
+					maxRetriesValidator{},
+				},
+			},
+			"retry_max_wait": schema.StringAttribute{
+				Description: fmt.Sprintf(
+					"Maximum time to wait between two retries as a duration like \"10s\", defaults to \"%s\"",
+					defaultRetryMaxWait,
+				),
+				Optional: true,
+			},
+			"request_timeout": schema.StringAttribute{
+				Description: fmt.Sprintf(
+					"Maximum time to wait for a response to a single request as a duration like \"30s\", "+
+						"defaults to \"%s\". Requests which time out are retried. Set to \"0s\" to disable the timeout.",
+					defaultRequestTimeout,
+				),
+				Optional: true,
+			},
+			"headers": schema.MapAttribute{
+				Description: "Headers sent with every request, e.g. for an API gateway in front of the Resume API. " +
+					"Headers managed by the provider such as Authorization cannot be set.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"requests_per_second": schema.Float64Attribute{
+				Description: "Maximum number of requests per second sent to the API. " +
+					"The rate is lowered automatically while the API responds with 429 Too Many Requests. " +
+					"Requests are not rate limited if unset or 0.",
+				Optional: true,
+			},
+			"request_burst": schema.Int64Attribute{
+				Description: fmt.Sprintf(
+					"Maximum number of requests sent at once before requests_per_second applies, defaults to %d",
+					defaultRequestBurst,
+				),
+				Optional: true,
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				Description: "PEM encoded CA certificate trusted in addition to the system certificates, " +
+					"conflicts with ca_cert_file",
+				Optional: true,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				Description: "Path to a PEM encoded CA certificate trusted in addition to the system certificates, " +
+					"conflicts with ca_cert_pem",
+				Optional: true,
+			},
+			"client_cert_pem": schema.StringAttribute{
+				Description: "PEM encoded client certificate for mutual TLS, requires client_key_pem",
+				Optional:    true,
+			},
+			"client_key_pem": schema.StringAttribute{
+				Description: "PEM encoded private key of client_cert_pem",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Description: "Skip the verification of the API server certificate, do not use in production",
+				Optional:    true,
+			},
+			"proxy_url": schema.StringAttribute{
+				Description: "URL of the proxy for API requests, " +
+					"defaults to the HTTP_PROXY and HTTPS_PROXY environment variables",
+				Optional: true,
+			},
+			"conflict_policy": schema.StringAttribute{
+				Description: fmt.Sprintf(
+					"What to do when a resume was changed outside of Terraform since it was last read, "+
+						"either \"%s\" to fail the update or delete, or \"%s\" to replace the remote changes. "+
+						"Defaults to \"%s\", which requires the Resume API to issue strong ETags.",
+					conflictPolicyFail, conflictPolicyOverwrite, conflictPolicyFail,
+				),
+				Optional: true,
+			},
+			"skip_preflight": schema.BoolAttribute{
+				Description: "Skip checking the endpoint, the token and the version of the Resume API " +
+					"while configuring the provider, e.g. if the API is not reachable while planning",
+				Optional: true,
+			},
+			"default_region": schema.StringAttribute{
+				Description: "ISO 3166-1 alpha-2 code of the region of phone numbers in national format, " +
+					"e.g. \"US\". Without it phone numbers must be in international format.",
+				Optional: true,
+			},
+			"allowed_url_domains": schema.ListAttribute{
+				Description: "Domains URLs such as the website of resumes must belong to, " +
+					"including their subdomains. Every domain is allowed if not set.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"denied_url_domains": schema.ListAttribute{
+				Description: "Domains URLs such as the website of resumes must not belong to, " +
+					"including their subdomains. Takes precedence over allowed_url_domains.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"auth": schema.SingleNestedBlock{
+				Description: "Short-lived credentials instead of a static token, " +
+					"exactly one of client_credentials, token_file and token_command must be set",
+				Attributes: map[string]schema.Attribute{
+					"token_file": schema.StringAttribute{
+						Description: "Path to a file containing the token, it is read again whenever it changes",
+						Optional:    true,
+					},
+					"token_command": schema.ListAttribute{
+						Description: "Command printing the token, either plain or as JSON object " +
+							"with token and expires_at keys. The output is cached until the token expires.",
+						ElementType: types.StringType,
+						Optional:    true,
+					},
+				},
+				Blocks: map[string]schema.Block{
+					"client_credentials": schema.SingleNestedBlock{
+						Description: "OAuth2 client credentials grant, tokens are refreshed before they expire",
+						Attributes: map[string]schema.Attribute{
+							"token_url": schema.StringAttribute{
+								Description: "OAuth2 token endpoint",
+								Optional:    true,
+							},
+							"client_id": schema.StringAttribute{
+								Description: "OAuth2 client ID",
+								Optional:    true,
+							},
+							"client_secret": schema.StringAttribute{
+								Description: "OAuth2 client secret",
+								Optional:    true,
+								Sensitive:   true,
+							},
+							"scopes": schema.ListAttribute{
+								Description: "OAuth2 scopes to request",
+								ElementType: types.StringType,
+								Optional:    true,
+							},
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -77,6 +295,14 @@ func (p *ResumeProvider) Configure(
 		)
 	}
 
+	if config.Endpoints.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("endpoints"),
+			"Unknown Endpoints configuration",
+			"The provider cannot create a client with an unknown value.",
+		)
+	}
+
 	if config.Token.IsUnknown() {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("token"),
@@ -100,7 +326,42 @@ func (p *ResumeProvider) Configure(
 		token = config.Token.ValueString()
 	}
 
-	if endpoint == "" {
+	if config.Auth != nil {
+		if !config.Token.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("token"),
+				"Conflicting Resume API Token configuration",
+				"Only one of token and the auth block may be set.",
+			)
+		}
+		token = ""
+	}
+
+	var endpoints []string
+	if !config.Endpoints.IsNull() {
+		if !config.Endpoint.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("endpoints"),
+				"Conflicting Resume API Endpoint configuration",
+				"Only one of endpoint and endpoints may be set.",
+			)
+		}
+
+		resp.Diagnostics.Append(config.Endpoints.ElementsAs(ctx, &endpoints, false)...)
+		for i, e := range endpoints {
+			if e == "" {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("endpoints").AtListIndex(i),
+					"Missing Resume API Endpoint",
+					"Endpoints must not be empty.",
+				)
+			}
+		}
+	} else if endpoint != "" {
+		endpoints = []string{endpoint}
+	}
+
+	if len(endpoints) == 0 {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("endpoint"),
 			"Missing Resume API Endpoint",
@@ -108,24 +369,151 @@ func (p *ResumeProvider) Configure(
 		)
 	}
 
-	if token == "" {
+	baseURLs, unixSockets, diags := resolveEndpoints(endpoints, !config.Endpoints.IsNull())
+	resp.Diagnostics.Append(diags...)
+
+	if token == "" && config.Auth == nil {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("token"),
 			"Missing Resume API Token",
-			"Cannot create the Resume client without a valid token.",
+			"Cannot create the Resume client without a valid token or an auth block.",
 		)
 	}
 
+	maxRetries := defaultMaxRetries
+	if !config.MaxRetries.IsNull() && !config.MaxRetries.IsUnknown() {
+		maxRetries = int(config.MaxRetries.ValueInt64())
+	}
+
+	retryMaxWait := defaultRetryMaxWait
+	if !config.RetryMaxWait.IsNull() && !config.RetryMaxWait.IsUnknown() {
+		var err error
+		retryMaxWait, err = time.ParseDuration(config.RetryMaxWait.ValueString())
+		if err == nil && retryMaxWait < 0 {
+			err = fmt.Errorf("duration must not be negative")
+		}
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("retry_max_wait"),
+				"Invalid Retry Max Wait",
+				fmt.Sprintf("Cannot parse duration: %v", err),
+			)
+		}
+	}
+
+	requestTimeout := defaultRequestTimeout
+	if !config.RequestTimeout.IsNull() && !config.RequestTimeout.IsUnknown() {
+		var err error
+		requestTimeout, err = time.ParseDuration(config.RequestTimeout.ValueString())
+		if err == nil && requestTimeout < 0 {
+			err = fmt.Errorf("duration must not be negative")
+		}
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("request_timeout"),
+				"Invalid Request Timeout",
+				fmt.Sprintf("Cannot parse duration: %v", err),
+			)
+		}
+	}
+
+	headers, diags := config.headers(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	var requestsPerSecond float64
+	if !config.RequestsPerSecond.IsNull() && !config.RequestsPerSecond.IsUnknown() {
+		requestsPerSecond = config.RequestsPerSecond.ValueFloat64()
+		if requestsPerSecond < 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("requests_per_second"),
+				"Invalid Requests Per Second",
+				"The request rate must not be negative.",
+			)
+		}
+	}
+
+	requestBurst := defaultRequestBurst
+	if !config.RequestBurst.IsNull() && !config.RequestBurst.IsUnknown() {
+		requestBurst = int(config.RequestBurst.ValueInt64())
+		if requestBurst < 1 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("request_burst"),
+				"Invalid Request Burst",
+				"The request burst must be at least 1.",
+			)
+		}
+	}
+
+	conflictPolicy := conflictPolicyFail
+	if !config.ConflictPolicy.IsNull() && !config.ConflictPolicy.IsUnknown() {
+		conflictPolicy = config.ConflictPolicy.ValueString()
+		if conflictPolicy != conflictPolicyFail && conflictPolicy != conflictPolicyOverwrite {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("conflict_policy"),
+				"Invalid Conflict Policy",
+				fmt.Sprintf("The conflict policy must be %q or %q.", conflictPolicyFail, conflictPolicyOverwrite),
+			)
+		}
+	}
+
+	var defaultRegion string
+	if !config.DefaultRegion.IsNull() && !config.DefaultRegion.IsUnknown() {
+		defaultRegion = strings.ToUpper(config.DefaultRegion.ValueString())
+		if !validRegion(defaultRegion) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("default_region"),
+				"Invalid Default Region",
+				fmt.Sprintf("%q is not an ISO 3166-1 alpha-2 code of a region with a phone numbering plan.",
+					config.DefaultRegion.ValueString()),
+			)
+		}
+	}
+
+	var urlDomains urlDomains
+	urlDomains.allowed, diags = urlDomainList(ctx, path.Root("allowed_url_domains"), config.AllowedURLDomains)
+	resp.Diagnostics.Append(diags...)
+	urlDomains.denied, diags = urlDomainList(ctx, path.Root("denied_url_domains"), config.DeniedURLDomains)
+	resp.Diagnostics.Append(diags...)
+
+	transport, diags := config.transport(unixSockets)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var roundTripper http.RoundTripper = transport
+	if p.wrapTransport != nil {
+		roundTripper = p.wrapTransport(roundTripper)
+	}
+	httpClient := &http.Client{Transport: roundTripper}
+
+	var tokens tokenSource = staticToken(token)
+	if config.Auth != nil {
+		tokens, diags = config.Auth.tokenSource(ctx, httpClient)
+		resp.Diagnostics.Append(diags...)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	ctx = tflog.SetField(ctx, "endpoint", endpoint)
+	ctx = tflog.SetField(ctx, "endpoints", endpoints)
 	ctx = tflog.SetField(ctx, "resume_token", token)
 	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "resume_token")
 	tflog.Debug(ctx, "Creating new client for Resume API")
 
-	client := newClient(endpoint, token, withUserAgent("Resume Provider"))
+	client := newClient(
+		baseURLs[0],
+		token,
+		withEndpoints(baseURLs),
+		withUserAgent(fmt.Sprintf("%s/%s", serviceName, p.version)),
+		withHeaders(headers),
+		withRetry(maxRetries, retryMaxWait),
+		withRequestTimeout(requestTimeout),
+		withRateLimit(requestsPerSecond, requestBurst),
+		withHTTPClient(httpClient),
+		withTokenSource(tokens),
+	)
 	//if err != nil {
 	//	resp.Diagnostics.AddError(
 	//		"Could not create client",
@@ -134,8 +522,218 @@ func (p *ResumeProvider) Configure(
 	//	return
 	//}
 
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	var serverVersion *version.Version
+	if !config.SkipPreflight.ValueBool() {
+		endpointPath := path.Root("endpoint")
+		if !config.Endpoints.IsNull() {
+			endpointPath = path.Root("endpoints")
+		}
+		serverVersion, diags = preflight(ctx, client, endpointPath)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	attributeVersions := p.attributeVersions
+	if attributeVersions == nil {
+		attributeVersions = resumeAttributeVersions
+	}
+
+	data := &providerData{
+		client:            client,
+		conflictPolicy:    conflictPolicy,
+		serverVersion:     serverVersion,
+		attributeVersions: attributeVersions,
+		defaultRegion:     defaultRegion,
+		urlDomains:        urlDomains,
+	}
+	resp.DataSourceData = data
+	resp.ResourceData = data
+}
+
+// urlDomainList returns the lower cased domains in list, which are
+// configured at p.
+func urlDomainList(ctx context.Context, p path.Path, list types.List) ([]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if list.IsNull() || list.IsUnknown() {
+		return nil, diags
+	}
+
+	var domains []string
+	diags.Append(list.ElementsAs(ctx, &domains, false)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	for i, domain := range domains {
+		domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+		if domain == "" || strings.ContainsAny(domain, "/:@ ") {
+			diags.AddAttributeError(
+				p.AtListIndex(i),
+				"Invalid URL Domain",
+				fmt.Sprintf("%q is not a domain name like \"example.com\".", domains[i]),
+			)
+		}
+		domains[i] = domain
+	}
+	return domains, diags
+}
+
+// headers returns the headers to send with every request.
+func (m ResumeProviderModel) headers(ctx context.Context) (map[string]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if m.Headers.IsNull() || m.Headers.IsUnknown() {
+		return nil, diags
+	}
+
+	headers := map[string]string{}
+	diags.Append(m.Headers.ElementsAs(ctx, &headers, false)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	for key, value := range headers {
+		switch {
+		case !httpguts.ValidHeaderFieldName(key):
+			diags.AddAttributeError(
+				path.Root("headers").AtMapKey(key),
+				"Invalid Header",
+				fmt.Sprintf("%q is not a valid header name.", key),
+			)
+		case !httpguts.ValidHeaderFieldValue(value):
+			diags.AddAttributeError(
+				path.Root("headers").AtMapKey(key),
+				"Invalid Header",
+				fmt.Sprintf("The value of %s must not contain control characters.", key),
+			)
+		}
+		for _, managed := range managedHeaders {
+			if strings.EqualFold(key, managed) {
+				diags.AddAttributeError(
+					path.Root("headers").AtMapKey(key),
+					"Invalid Header",
+					fmt.Sprintf("%s is set by the provider and cannot be configured.", managed),
+				)
+			}
+		}
+	}
+
+	return headers, diags
+}
+
+// resolveEndpoints returns the base URLs of endpoints, which are taken from
+// the endpoints attribute if fromList is set, and the Unix domain sockets
+// the transport dials for them.
+func resolveEndpoints(endpoints []string, fromList bool) ([]string, map[string]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	baseURLs := make([]string, len(endpoints))
+	unixSockets := map[string]string{}
+	for i, endpoint := range endpoints {
+		if !isUnixEndpoint(endpoint) {
+			baseURLs[i] = endpoint
+			continue
+		}
+
+		baseURL, socket, err := unixBaseURL(endpoint)
+		if err != nil {
+			attrPath := path.Root("endpoint")
+			if fromList {
+				attrPath = path.Root("endpoints").AtListIndex(i)
+			}
+			diags.AddAttributeError(attrPath, "Invalid Resume API Endpoint", err.Error())
+			continue
+		}
+		baseURLs[i] = baseURL
+		unixSockets[unixSocketHost(socket)] = socket
+	}
+
+	return baseURLs, unixSockets, diags
+}
+
+// transport builds the transport for the client from the TLS and proxy
+// settings of the provider, dialing unixSockets for their host names.
+func (m ResumeProviderModel) transport(unixSockets map[string]string) (*http.Transport, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	cfg := transportConfig{
+		CACertPEM:          m.CACertPEM.ValueString(),
+		ClientCertPEM:      m.ClientCertPEM.ValueString(),
+		ClientKeyPEM:       m.ClientKeyPEM.ValueString(),
+		InsecureSkipVerify: m.InsecureSkipVerify.ValueBool(),
+		ProxyURL:           m.ProxyURL.ValueString(),
+		UnixSockets:        unixSockets,
+	}
+
+	if !m.CACertFile.IsNull() {
+		if cfg.CACertPEM != "" {
+			diags.AddAttributeError(
+				path.Root("ca_cert_file"),
+				"Conflicting CA Certificate configuration",
+				"Only one of ca_cert_pem and ca_cert_file may be set.",
+			)
+			return nil, diags
+		}
+
+		caCert, err := os.ReadFile(m.CACertFile.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("ca_cert_file"),
+				"Unable to read CA Certificate",
+				err.Error(),
+			)
+			return nil, diags
+		}
+		cfg.CACertPEM = string(caCert)
+	}
+
+	if (cfg.ClientCertPEM == "") != (cfg.ClientKeyPEM == "") {
+		diags.AddAttributeError(
+			path.Root("client_cert_pem"),
+			"Incomplete Client Certificate configuration",
+			"Both client_cert_pem and client_key_pem must be set for mutual TLS.",
+		)
+		return nil, diags
+	}
+
+	transport, err := newTransport(cfg)
+	if err != nil {
+		diags.AddError(
+			"Invalid TLS or proxy configuration",
+			err.Error(),
+		)
+		return nil, diags
+	}
+
+	return transport, diags
+}
+
+// maxRetriesValidator validates max_retries between 0 and maxRetriesLimit.
+type maxRetriesValidator struct{}
+
+func (v maxRetriesValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("value must be between 0 and %d", maxRetriesLimit)
+}
+
+func (v maxRetriesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v maxRetriesValidator) ValidateInt64(
+	ctx context.Context, req validator.Int64Request, resp *validator.Int64Response,
+) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if value := req.ConfigValue.ValueInt64(); value < 0 || value > maxRetriesLimit {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Max Retries", fmt.Sprintf(
+			"The number of retries must be between 0 and %d, got %d.", maxRetriesLimit, value,
+		))
+	}
 }
 
 func (p *ResumeProvider) Resources(ctx context.Context) []func() resource.Resource {
@@ -4,9 +4,16 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"net/http"
 	"os"
 	"testing"
 )
@@ -18,21 +25,45 @@ provider "resume" {
 }
 `
 
+// providerConfig configures the provider for acceptance tests, the endpoint
+// and token do not matter when replaying a cassette.
 var providerConfig = fmt.Sprintf(
 	providerConfigTemplate,
-	os.Getenv("RESUME_API_ENDPOINT"),
-	os.Getenv("RESUME_API_TOKEN"),
+	testAccEnv("RESUME_API_ENDPOINT", "http://resume.invalid"),
+	testAccEnv("RESUME_API_TOKEN", "replay"),
 )
 
+func testAccEnv(key, replayValue string) string {
+	if value := os.Getenv(key); value != "" || testAccCassetteMode() != "replay" {
+		return value
+	}
+	return replayValue
+}
+
 // testAccProtoV6ProviderFactories are used to instantiate a provider during
 // acceptance testing. The factory function will be invoked for every Terraform
 // CLI command executed to create a provider server to which the CLI can
-// reattach.
-var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
-	"resume": providerserver.NewProtocol6WithError(New("test")()),
+// reattach. Every provider of t records to or replays from its cassette, see
+// testAccRecorder.
+func testAccProtoV6ProviderFactories(t *testing.T) map[string]func() (tfprotov6.ProviderServer, error) {
+	t.Helper()
+
+	r := testAccRecorder(t)
+	return map[string]func() (tfprotov6.ProviderServer, error){
+		"resume": func() (tfprotov6.ProviderServer, error) {
+			p := &ResumeProvider{version: "test"}
+			if r != nil {
+				p.wrapTransport = r.Transport
+			}
+			return providerserver.NewProtocol6WithError(p)()
+		},
+	}
 }
 
 func testAccPreCheck(t *testing.T) {
+	if testAccCassetteMode() == "replay" {
+		return
+	}
 	if v := os.Getenv("RESUME_API_ENDPOINT"); v == "" {
 		t.Fatal("RESUME_API_ENDPOINT must be set for acceptance tests")
 	}
@@ -40,3 +71,110 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatal("RESUME_API_TOKEN must be set for acceptance tests")
 	}
 }
+
+func TestProviderHeaders(t *testing.T) {
+	api := newFakeAPI(t)
+	config := api.config()
+	config.Headers = types.MapValueMust(types.StringType, map[string]attr.Value{
+		"X-Tenant":          types.StringValue("acme"),
+		"X-Api-Gateway-Key": types.StringValue("secret"),
+	})
+	api.provider(config)
+
+	header := api.Header()
+	if header.Get("X-Tenant") != "acme" || header.Get("X-Api-Gateway-Key") != "secret" {
+		t.Errorf("expected configured headers in preflight request, got %v", header)
+	}
+	if ua := header.Get("User-Agent"); ua != "terraform-provider-resume/test" {
+		t.Errorf("expected User-Agent with provider version, got %q", ua)
+	}
+}
+
+func TestProviderHeadersInvalid(t *testing.T) {
+	for _, name := range []string{"Authorization", "content-type", "X Tenant"} {
+		t.Run(name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			config := api.config()
+			config.Headers = types.MapValueMust(types.StringType, map[string]attr.Value{
+				name: types.StringValue("foo"),
+			})
+
+			_, diags := newTestProvider(t, config)
+
+			errs := diags.Errors()
+			if len(errs) != 1 || errs[0].Summary() != "Invalid Header" {
+				t.Fatalf("expected an Invalid Header error, got %v", diags)
+			}
+			expected := path.Root("headers").AtMapKey(name)
+			if withPath, ok := errs[0].(diag.DiagnosticWithPath); !ok || !withPath.Path().Equal(expected) {
+				t.Errorf("expected error at %s, got %v", expected, errs[0])
+			}
+		})
+	}
+}
+
+func TestMaxRetriesValidator(t *testing.T) {
+	cases := map[int64]bool{-1: false, 0: true, 3: true, maxRetriesLimit: true, maxRetriesLimit + 1: false, 64: false}
+
+	for value, valid := range cases {
+		req := validator.Int64Request{Path: path.Root("max_retries"), ConfigValue: types.Int64Value(value)}
+		var resp validator.Int64Response
+		maxRetriesValidator{}.ValidateInt64(context.Background(), req, &resp)
+
+		if resp.Diagnostics.HasError() == valid {
+			t.Errorf("%d: expected valid %t, got %v", value, valid, resp.Diagnostics)
+		}
+	}
+}
+
+func TestProviderEndpoints(t *testing.T) {
+	primary := newFakeAPI(t)
+	secondary := newFakeAPI(t)
+	primary.Close()
+
+	config := primary.config()
+	config.Endpoint = types.StringNull()
+	config.Endpoints = types.ListValueMust(types.StringType, []attr.Value{
+		types.StringValue(primary.URL), types.StringValue(secondary.URL),
+	})
+	config.MaxRetries = types.Int64Value(0)
+
+	if _, diags := newTestProvider(t, config); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if requests := secondary.Requests(); len(requests) != 2 {
+		t.Errorf("expected a probe and the preflight on the secondary endpoint, got %v", requests)
+	}
+
+	config.Endpoint = types.StringValue(secondary.URL)
+	_, diags := newTestProvider(t, config)
+	if errs := diags.Errors(); len(errs) != 1 || errs[0].Summary() != "Conflicting Resume API Endpoint configuration" {
+		t.Errorf("expected a conflicting endpoint error, got %v", diags)
+	}
+}
+
+func TestProviderUnixSocket(t *testing.T) {
+	var requested string
+	socket := newUnixServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path
+		_, _ = w.Write([]byte(`{"name":"Resume API","version":"1.0.0","environment":"development"}`))
+	}))
+
+	api := newFakeAPI(t)
+	config := api.config()
+	config.Endpoint = types.StringValue("unix://" + socket + "/api")
+	config.ProxyURL = types.StringValue("http://proxy.invalid")
+
+	if _, diags := newTestProvider(t, config); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if requested != "/api/info" {
+		t.Errorf("expected the preflight to request /api/info through the socket, got %q", requested)
+	}
+
+	config.Endpoint = types.StringValue("unix://localhost" + socket)
+	_, diags := newTestProvider(t, config)
+	if errs := diags.Errors(); len(errs) != 1 || errs[0].Summary() != "Invalid Resume API Endpoint" {
+		t.Errorf("expected an invalid endpoint error, got %v", diags)
+	}
+}
@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"context"
+	"golang.org/x/time/rate"
+	"sync"
+)
+
+// defaultRequestBurst applies when requests_per_second is set without
+// request_burst. Requests are not rate limited by default.
+const defaultRequestBurst = 10
+
+// adaptiveLimiter is a token bucket shared by all requests of a client. The
+// configured rate is an upper bound: it is halved whenever the API responds
+// with 429 Too Many Requests and slowly recovers with successful requests.
+type adaptiveLimiter struct {
+	mu      sync.Mutex
+	limiter *rate.Limiter
+	max     rate.Limit
+	min     rate.Limit
+}
+
+func newAdaptiveLimiter(requestsPerSecond float64, burst int) *adaptiveLimiter {
+	limit := rate.Limit(requestsPerSecond)
+
+	return &adaptiveLimiter{
+		limiter: rate.NewLimiter(limit, burst),
+		max:     limit,
+		min:     limit / 32,
+	}
+}
+
+func (l *adaptiveLimiter) Wait(ctx context.Context) error {
+	return l.limiter.Wait(ctx)
+}
+
+func (l *adaptiveLimiter) Limit() rate.Limit {
+	return l.limiter.Limit()
+}
+
+// Throttled halves the rate, down to 1/32 of the configured rate.
+func (l *adaptiveLimiter) Throttled() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limit := l.limiter.Limit() / 2
+	if limit < l.min {
+		limit = l.min
+	}
+	l.limiter.SetLimit(limit)
+}
+
+// Succeeded raises the rate by a tenth of the configured rate, up to the
+// configured rate.
+func (l *adaptiveLimiter) Succeeded() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limit := l.limiter.Limit()
+	if limit >= l.max {
+		return
+	}
+
+	limit += l.max / 10
+	if limit > l.max {
+		limit = l.max
+	}
+	l.limiter.SetLimit(limit)
+}
@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"context"
+	"golang.org/x/time/rate"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveLimiter(t *testing.T) {
+	l := newAdaptiveLimiter(10, 1)
+
+	l.Throttled()
+	if l.Limit() != 5 {
+		t.Errorf("expected limit 5 after throttling, got %v", l.Limit())
+	}
+
+	for i := 0; i < 10; i++ {
+		l.Throttled()
+	}
+	if l.Limit() != rate.Limit(10)/32 {
+		t.Errorf("expected limit to stop at 10/32, got %v", l.Limit())
+	}
+
+	for i := 0; i < 20; i++ {
+		l.Succeeded()
+	}
+	if l.Limit() != 10 {
+		t.Errorf("expected limit to recover to 10, got %v", l.Limit())
+	}
+}
+
+func TestClientRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := newClient(server.URL, "foo", withRateLimit(50, 1))
+
+	// 6 requests with a burst of 1 at 50 requests per second take at least 100ms,
+	// regardless of how many goroutines share the client.
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := c.Get(context.Background(), "/info")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("expected requests to be rate limited, took %s", elapsed)
+	}
+}
+
+func TestClientRateLimitDisabled(t *testing.T) {
+	c := newClient("http://localhost", "foo", withRateLimit(0, 1))
+	if c.limiter != nil {
+		t.Error("expected rate limiting to be disabled")
+	}
+
+	if c := newClient("http://localhost", "foo"); c.limiter != nil {
+		t.Error("expected rate limiting to be disabled by default")
+	}
+}
+
+func TestClientRateLimitThrottled(t *testing.T) {
+	server, _ := newFlakyServer(t, 2, http.StatusTooManyRequests, nil)
+
+	c := newClient(server.URL, "foo", withRateLimit(100, 1), withRetry(2, 10*time.Millisecond))
+	c.retryMinWait = time.Millisecond
+
+	resp, err := c.Get(context.Background(), "/info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	// Halved twice, raised by a tenth once.
+	if limit := c.limiter.Limit(); limit != 35 {
+		t.Errorf("expected limit 35, got %v", limit)
+	}
+}
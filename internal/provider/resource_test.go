@@ -1,75 +0,0 @@
-package provider
-
-import (
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"net/http/httptest"
-	"sync/atomic"
-	"testing"
-)
-
-// Source: https://github.com/BetterStackHQ/terraform-provider-better-uptime/blob/master/internal/provider/resource_test.go
-
-//nolint:unused
-func newResourceServer(t *testing.T, baseRequestURI, id string, fieldsNotReturnedFromApi ...string) *httptest.Server {
-	var data atomic.Value
-	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		t.Log("Received " + r.Method + " " + r.RequestURI)
-
-		if r.Header.Get("Authorization") != "Bearer foo" {
-			t.Fatal("Not authorized: " + r.Header.Get("Authorization"))
-		}
-
-		switch {
-		case r.Method == http.MethodPost && r.RequestURI == baseRequestURI:
-			body, err := io.ReadAll(r.Body)
-			if err != nil {
-				t.Fatal(err)
-			}
-
-			parsedBody := make(map[string]interface{})
-			if err := json.Unmarshal(body, &parsedBody); err != nil {
-				t.Fatal(err)
-			}
-			for _, field := range fieldsNotReturnedFromApi {
-				delete(parsedBody, field)
-			}
-			body, err = json.Marshal(parsedBody)
-			if err != nil {
-				t.Fatal(err)
-			}
-
-			data.Store(body)
-			w.WriteHeader(http.StatusCreated)
-			_, _ = w.Write([]byte(fmt.Sprintf(`{"data":{"id":%q,"attributes":%s}}`, id, body)))
-		case r.Method == http.MethodGet && r.RequestURI == baseRequestURI+"/"+id:
-			_, _ = w.Write([]byte(fmt.Sprintf(`{"data":{"id":%q,"attributes":%s}}`, id, data.Load().([]byte))))
-		case r.Method == http.MethodPatch && r.RequestURI == baseRequestURI+"/"+id:
-			body, err := io.ReadAll(r.Body)
-			if err != nil {
-				t.Fatal(err)
-			}
-			patch := make(map[string]interface{})
-			//nolint:forcetypeassert
-			if err = json.Unmarshal(data.Load().([]byte), &patch); err != nil {
-				t.Fatal(err)
-			}
-			if err = json.Unmarshal(body, &patch); err != nil {
-				t.Fatal(err)
-			}
-			patched, err := json.Marshal(patch)
-			if err != nil {
-				t.Fatal(err)
-			}
-			data.Store(patched)
-			_, _ = w.Write([]byte(fmt.Sprintf(`{"data":{"id":%q,"attributes":%s}}`, id, patched)))
-		case r.Method == http.MethodDelete && r.RequestURI == baseRequestURI+"/"+id:
-			w.WriteHeader(http.StatusNoContent)
-			data.Store([]byte(nil))
-		default:
-			t.Fatal("Unexpected " + r.Method + " " + r.RequestURI)
-		}
-	}))
-}
@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/lagerfeuer/terraform-provider-resume/internal/resumeapi"
+	"io"
+	"sort"
+	"strings"
+)
+
+// DoJSON implements resumeapi.Doer, every call of the Resume API goes through
+// it. It sends the body of req as JSON, checks the status of the response,
+// decodes its JSON body into out unless it is nil and always drains and
+// closes the body. Failures are returned as diagnostics summarized by
+// req.Summary, together with the status code of the response if there was
+// one.
+func (c *client) DoJSON(
+	ctx context.Context, req resumeapi.Request, out interface{},
+) (resumeapi.Response, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var body io.Reader
+	if req.Body != nil {
+		payload, err := json.Marshal(req.Body)
+		if err != nil {
+			diags.AddError(req.Summary, fmt.Sprintf("Could not encode the request body: %v", err))
+			return resumeapi.Response{}, diags
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	resp, err := c.do(ctx, req.Method, req.Path, body, req.Header)
+	if err != nil {
+		var result resumeapi.Response
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			result.StatusCode = apiErr.StatusCode
+		}
+		addAPIError(&diags, req.Summary, err, req.AttributePaths)
+		return result, diags
+	}
+	defer func() {
+		// Keep-Alive.
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	result := resumeapi.Response{StatusCode: resp.StatusCode, Header: resp.Header}
+	if resp.StatusCode != req.Status {
+		diags.AddError(req.Summary, fmt.Sprintf(
+			"%s %s returned %d, expected %d", req.Method, req.Path, resp.StatusCode, req.Status,
+		))
+		return result, diags
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			diags.AddError(req.Summary, fmt.Sprintf(
+				"Could not decode the response body of %s %s: %v", req.Method, req.Path, err,
+			))
+		}
+	}
+
+	return result, diags
+}
+
+// addAPIError adds err to diags. Validation errors returned by the API are
+// attached to the attribute their field maps to in attributePaths.
+func addAPIError(diags *diag.Diagnostics, summary string, err error, attributePaths map[string]path.Path) {
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) {
+		diags.AddError(summary, fmt.Sprintf(
+			"The Resume API did not respond in time: %v\n\n"+
+				"The timeout of a single request can be increased through request_timeout in the provider configuration.",
+			err,
+		))
+		return
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || len(apiErr.Errors) == 0 {
+		diags.AddError(summary, err.Error())
+		return
+	}
+
+	fields := make([]string, 0, len(apiErr.Errors))
+	for field := range apiErr.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		for _, msg := range apiErr.Errors[field] {
+			detail := fieldErrorMessage(field, msg)
+			if apiErr.RequestID != "" {
+				detail = fmt.Sprintf("%s (request ID %s)", detail, apiErr.RequestID)
+			}
+
+			if attr, ok := attributePaths[field]; ok {
+				diags.AddAttributeError(attr, summary, detail)
+			} else {
+				diags.AddError(summary, detail)
+			}
+		}
+	}
+}
+
+// errorDetail joins the details of the errors in diags, e.g. to log them.
+func errorDetail(diags diag.Diagnostics) string {
+	details := make([]string, 0, diags.ErrorsCount())
+	for _, d := range diags.Errors() {
+		details = append(details, d.Detail())
+	}
+	return strings.Join(details, "; ")
+}
@@ -0,0 +1,167 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/lagerfeuer/terraform-provider-resume/internal/resumeapi"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientDoJSON(t *testing.T) {
+	type resume struct {
+		Id   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+
+	tests := map[string]struct {
+		status   int
+		response string
+		// detail is a substring of the expected error, none is expected if
+		// empty.
+		detail string
+		path   path.Path
+	}{
+		"success": {
+			status:   http.StatusCreated,
+			response: `{"id":1,"name":"Michael G Scott"}`,
+		},
+		"unexpected status": {
+			status:   http.StatusOK,
+			response: `{"id":1,"name":"Michael G Scott"}`,
+			detail:   "POST /resumes returned 200, expected 201",
+		},
+		"invalid body": {
+			status:   http.StatusCreated,
+			response: `{"id":`,
+			detail:   "Could not decode the response body of POST /resumes",
+		},
+		"validation error": {
+			status:   http.StatusUnprocessableEntity,
+			response: `{"name":["can't be blank"]}`,
+			detail:   "name can't be blank",
+			path:     path.Root("name"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var received string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				received = string(body)
+				w.WriteHeader(test.status)
+				_, _ = w.Write([]byte(test.response))
+			}))
+			defer server.Close()
+
+			transport := &countingTransport{next: http.DefaultTransport}
+			c := newClient(server.URL, "foo", withHTTPClient(&http.Client{Transport: transport}))
+
+			var out resume
+			resp, diags := c.DoJSON(context.Background(), resumeapi.Request{
+				Summary: "Error creating Resume",
+				Method:  http.MethodPost,
+				Path:    "/resumes",
+				Body:    resume{Name: "Michael G Scott"},
+				Status:  http.StatusCreated,
+
+				AttributePaths: map[string]path.Path{"name": path.Root("name")},
+			}, &out)
+
+			if received != `{"id":0,"name":"Michael G Scott"}` {
+				t.Errorf("expected the body to be sent as JSON, got %s", received)
+			}
+			if resp.StatusCode != test.status {
+				t.Errorf("expected status %d, got %d", test.status, resp.StatusCode)
+			}
+			if test.detail == "" {
+				if diags.HasError() {
+					t.Fatal(diags)
+				}
+				if out.Id != 1 || out.Name != "Michael G Scott" {
+					t.Errorf("expected the response to be decoded, got %+v", out)
+				}
+			} else {
+				if diags.ErrorsCount() != 1 || diags[0].Summary() != "Error creating Resume" ||
+					!strings.Contains(diags[0].Detail(), test.detail) {
+					t.Fatalf("expected an error containing %q, got %v", test.detail, diags)
+				}
+				var actual path.Path
+				if withPath, ok := diags[0].(diag.DiagnosticWithPath); ok {
+					actual = withPath.Path()
+				}
+				if !actual.Equal(test.path) {
+					t.Errorf("expected the error at %q, got %q", test.path, actual)
+				}
+			}
+
+			if opened, closed := transport.opened.Load(), transport.closed.Load(); opened != 1 || closed != 1 {
+				t.Errorf("expected 1 response body to be closed, got %d of %d", closed, opened)
+			}
+		})
+	}
+}
+
+func TestClientDoJSONUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	c := newClient(server.URL, "foo", withRetry(0, 0))
+	resp, diags := c.DoJSON(context.Background(), resumeapi.Request{
+		Summary: "Error reading Resume",
+		Method:  http.MethodGet,
+		Path:    "/resumes/1",
+		Status:  http.StatusOK,
+	}, nil)
+
+	if resp.StatusCode != 0 || !diags.HasError() {
+		t.Errorf("expected an error without status, got %d: %v", resp.StatusCode, diags)
+	}
+}
+
+func TestAddAPIError(t *testing.T) {
+	err := &APIError{
+		Method:     http.MethodPost,
+		Path:       "/resumes",
+		StatusCode: http.StatusUnprocessableEntity,
+		Errors: map[string][]string{
+			"name":         {"can't be blank"},
+			"phone_number": {"is invalid"},
+			"base":         {"Resume limit reached"},
+		},
+	}
+
+	var diags diag.Diagnostics
+	addAPIError(&diags, "Error creating Resume", err, resumeAttributePaths)
+
+	if diags.ErrorsCount() != 3 {
+		t.Fatalf("expected 3 errors, got %d: %v", diags.ErrorsCount(), diags)
+	}
+
+	expected := []struct {
+		path   path.Path
+		detail string
+	}{
+		{path: path.Empty(), detail: "Resume limit reached"},
+		{path: path.Root("name"), detail: "name can't be blank"},
+		{path: path.Root("phone_number"), detail: "phone_number is invalid"},
+	}
+	for i, e := range expected {
+		d := diags[i]
+		var actual path.Path
+		if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+			actual = withPath.Path()
+		}
+		if actual.String() != e.path.String() {
+			t.Errorf("expected diagnostic %d at %q, got %q", i, e.path, actual)
+		}
+		if d.Detail() != e.detail {
+			t.Errorf("expected diagnostic %d detail %q, got %q", i, e.detail, d.Detail())
+		}
+	}
+}
@@ -4,48 +4,89 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"io"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/lagerfeuer/terraform-provider-resume/internal/resumeapi"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 )
 
 var (
-	_ resource.Resource                = &resumeResource{}
-	_ resource.ResourceWithConfigure   = &resumeResource{}
-	_ resource.ResourceWithImportState = &resumeResource{}
+	_ resource.Resource                 = &resumeResource{}
+	_ resource.ResourceWithConfigure    = &resumeResource{}
+	_ resource.ResourceWithImportState  = &resumeResource{}
+	_ resource.ResourceWithModifyPlan   = &resumeResource{}
+	_ resource.ResourceWithUpgradeState = &resumeResource{}
 )
 
-var resumeEndpoint = "/resumes"
-
 func NewResumeResource() resource.Resource {
 	return &resumeResource{}
 }
 
 type resumeResource struct {
-	client *client
+	api               *resumeapi.Client
+	conflictPolicy    string
+	serverVersion     *version.Version
+	attributeVersions map[string]*version.Version
+	defaultRegion     string
+	urlDomains        urlDomains
 }
 
 type resumeResourceModel struct {
-	Id          types.String `tfsdk:"id"`
-	Name        types.String `tfsdk:"name"`
-	Address     types.String `tfsdk:"address"`
-	PhoneNumber types.String `tfsdk:"phone_number"`
-	Website     types.String `tfsdk:"website"`
+	Id          types.String        `tfsdk:"id"`
+	Name        types.String        `tfsdk:"name"`
+	Address     *resumeAddressModel `tfsdk:"address"`
+	PhoneNumber PhoneNumber         `tfsdk:"phone_number"`
+	Website     URL                 `tfsdk:"website"`
+
+	Emails   []resumeEmailModel   `tfsdk:"emails"`
+	Profiles []resumeProfileModel `tfsdk:"profiles"`
+
+	PhoneNumberE164 types.String `tfsdk:"phone_number_e164"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
-type resumeResourceJson struct {
-	Id          int64  `json:"id"`
-	Name        string `json:"name"`
-	Address     string `json:"address"`
-	PhoneNumber string `json:"phone_number"`
-	Website     string `json:"website"`
+// defaultResumeTimeout applies to every operation without a timeout in the
+// timeouts block.
+const defaultResumeTimeout = 5 * time.Minute
+
+// resumeURLAttributes are the paths of the URLType attributes outside of
+// profiles, their domains are checked while planning.
+var resumeURLAttributes = []path.Path{
+	path.Root("website"),
+}
+
+// resumeAttributePaths maps the fields of resumeapi.Resume, as named in API
+// validation errors, to the attributes of the resource schema.
+var resumeAttributePaths = map[string]path.Path{
+	"name":                 path.Root("name"),
+	"address":              path.Root("address"),
+	"address.street_lines": path.Root("address").AtName("street_lines"),
+	"address.city":         path.Root("address").AtName("city"),
+	"address.region":       path.Root("address").AtName("region"),
+	"address.postal_code":  path.Root("address").AtName("postal_code"),
+	"address.country_code": path.Root("address").AtName("country_code"),
+	"phone_number":         path.Root("phone_number"),
+	"website":              path.Root("website"),
+	"emails":               path.Root("emails"),
+	"profiles":             path.Root("profiles"),
 }
 
 func (r *resumeResource) Configure(
@@ -55,17 +96,23 @@ func (r *resumeResource) Configure(
 		return
 	}
 
-	client, ok := req.ProviderData.(*client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
 			fmt.Sprintf(
-				"Expected *client, got: %T. Please report this to the provider developer.",
+				"Expected *providerData, got: %T. Please report this to the provider developer.",
 				req.ProviderData,
 			),
 		)
+		return
 	}
-	r.client = client
+	r.api = resumeapi.New(data.client).WithAttributePaths(resumeAttributePaths)
+	r.conflictPolicy = data.conflictPolicy
+	r.serverVersion = data.serverVersion
+	r.attributeVersions = data.attributeVersions
+	r.defaultRegion = data.defaultRegion
+	r.urlDomains = data.urlDomains
 }
 
 func (r *resumeResource) Metadata(
@@ -78,6 +125,7 @@ func (r *resumeResource) Schema(
 	ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse,
 ) {
 	resp.Schema = schema.Schema{
+		Version: 1,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed: true,
@@ -88,107 +136,251 @@ func (r *resumeResource) Schema(
 			"name": schema.StringAttribute{
 				Required: true,
 			},
-			"address": schema.StringAttribute{
-				Computed: false,
-				Optional: true,
+			"address": schema.SingleNestedAttribute{
+				Description: "Postal address",
+				Attributes:  resumeAddressAttributes(),
+				Optional:    true,
 			},
 			"phone_number": schema.StringAttribute{
-				Computed: false,
-				Optional: true,
+				CustomType: PhoneNumberType{},
+				Computed:   false,
+				Optional:   true,
+				Validators: []validator.String{
+					phoneNumberValidator{},
+				},
+			},
+			"phone_number_e164": schema.StringAttribute{
+				Description: "phone_number in E.164 format, numbers in national format " +
+					"are taken to be from the default_region of the provider",
+				Computed: true,
 			},
 			"website": schema.StringAttribute{
-				Computed: false,
+				CustomType: URLType{},
+				Computed:   false,
+				Optional:   true,
+				Validators: []validator.String{
+					urlValidator{},
+				},
+			},
+			"emails": schema.ListNestedAttribute{
+				Description: "Email addresses, exactly one of them must be primary",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: resumeEmailAttributes(),
+				},
+				Optional: true,
+				Validators: []validator.List{
+					primaryEmailValidator{},
+				},
+			},
+			"profiles": schema.SetNestedAttribute{
+				Description: "Online profiles like in basics.profiles of JSON Resume",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: resumeProfileAttributes(),
+				},
 				Optional: true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
-func (r *resumeResource) Create(
-	ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse,
+// ModifyPlan plans phone_number_e164, checks the domains of URLs and warns
+// about configured attributes which the Resume API does not support yet.
+func (r *resumeResource) ModifyPlan(
+	ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse,
 ) {
-	var plan resumeResourceModel
-	diags := req.Plan.Get(ctx, &plan)
-	resp.Diagnostics.Append(diags...)
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var phoneNumber PhoneNumber
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("phone_number"), &phoneNumber)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	e164, diags := r.planPhoneNumberE164(phoneNumber)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("phone_number_e164"), e164)...)
 
-	var data resumeResourceJson
-	data.Name = plan.Name.ValueString()
-	data.Address = plan.Address.ValueString()
-	data.PhoneNumber = plan.PhoneNumber.ValueString()
-	data.Website = plan.Website.ValueString()
+	for _, p := range resumeURLAttributes {
+		var value URL
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, p, &value)...)
+		resp.Diagnostics.Append(r.checkURLDomain(p, value)...)
+	}
 
-	reqBodyBytes, err := json.Marshal(data)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Could not convert JSON to request body",
-			err.Error(),
-		)
-		return
+	var profiles types.Set
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("profiles"), &profiles)...)
+	for _, profile := range profiles.Elements() {
+		if object, ok := profile.(types.Object); ok {
+			if value, ok := object.Attributes()["url"].(URL); ok {
+				p := path.Root("profiles").AtSetValue(profile).AtName("url")
+				resp.Diagnostics.Append(r.checkURLDomain(p, value)...)
+			}
+		}
 	}
 
-	reqBody := bytes.NewReader(reqBodyBytes)
-	if reqBody == nil {
-		resp.Diagnostics.AddError(
-			"Could not create reader from bytes",
-			err.Error(),
-		)
-		return
+	r.warnUnsupportedAttributes(ctx, req, resp)
+}
+
+// planPhoneNumberE164 returns the planned phone_number_e164 of phoneNumber.
+func (r *resumeResource) planPhoneNumberE164(phoneNumber PhoneNumber) (types.String, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if phoneNumber.IsUnknown() {
+		return types.StringUnknown(), diags
+	}
+	if phoneNumber.IsNull() || phoneNumber.ValueString() == "" {
+		return types.StringNull(), diags
 	}
 
-	httpResp, err := r.client.Post(ctx, resumeEndpoint, reqBody)
+	e164, err := phoneNumberE164(phoneNumber.ValueString(), r.defaultRegion)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating Resume",
-			err.Error(),
+		diags.AddAttributeError(
+			path.Root("phone_number"),
+			"Invalid Phone Number",
+			fmt.Sprintf("%q is not a valid phone number: %v. "+
+				"Numbers in national format require default_region in the provider configuration.",
+				phoneNumber.ValueString(), err),
 		)
-		return
+		return types.StringUnknown(), diags
 	}
-	if httpResp.StatusCode != http.StatusCreated {
-		resp.Diagnostics.AddError(
-			"Error updating Resume",
-			fmt.Sprintf("Expected 201, got %d.", httpResp.StatusCode),
-		)
-		return
+	return types.StringValue(e164), diags
+}
+
+// checkURLDomain adds an error if the domain of the URL at p is not allowed
+// by the provider configuration.
+func (r *resumeResource) checkURLDomain(p path.Path, value URL) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if value.IsNull() || value.IsUnknown() || value.ValueString() == "" {
+		return diags
 	}
 
-	body, err := io.ReadAll(httpResp.Body)
+	u, err := parseURL(value.ValueString())
+	if err == nil {
+		err = r.urlDomains.check(u)
+	}
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to read HTTP response body",
-			err.Error(),
-		)
+		diags.AddAttributeError(p, "URL Domain Not Allowed", fmt.Sprintf(
+			"%q is not allowed: %v. The domains are restricted by allowed_url_domains and denied_url_domains "+
+				"in the provider configuration.", value.ValueString(), err,
+		))
+	}
+	return diags
+}
+
+// warnUnsupportedAttributes warns about configured attributes which the
+// Resume API does not support yet, see attributeVersions. Their values
+// would be ignored or rejected by the API.
+func (r *resumeResource) warnUnsupportedAttributes(
+	ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse,
+) {
+	if r.serverVersion == nil {
 		return
 	}
 
-	if err = json.Unmarshal(body, &data); err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to parse HTTP response body",
-			err.Error(),
+	names := make([]string, 0, len(r.attributeVersions))
+	for name := range r.attributeVersions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		since := r.attributeVersions[name]
+		if !r.serverVersion.LessThan(since) {
+			continue
+		}
+
+		var value attr.Value
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(name), &value)...)
+		if value == nil || value.IsNull() {
+			continue
+		}
+
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root(name),
+			"Attribute Not Supported by Resume API",
+			fmt.Sprintf("The Resume API runs version %s, but %s requires version %s or later. "+
+				"The API may ignore or reject its value.", r.serverVersion, name, since),
 		)
+	}
+}
+
+func (r *resumeResource) Create(
+	ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse,
+) {
+	ctx, span := startSpan(ctx, "resume_resume.Create")
+	defer func() { endSpan(span, resp.Diagnostics) }()
+
+	var plan resumeResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	plan.Id = types.StringValue(strconv.FormatInt(data.Id, 10))
-	plan.Name = types.StringValue(data.Name)
-	if data.Address == "" {
-		plan.Address = types.StringNull()
-	} else {
-		plan.Address = types.StringValue(data.Address)
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultResumeTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	if data.PhoneNumber == "" {
-		plan.PhoneNumber = types.StringNull()
-	} else {
-		plan.PhoneNumber = types.StringValue(data.PhoneNumber)
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	idempotencyKey, err := uuid.GenerateUUID()
+	if err != nil {
+		resp.Diagnostics.AddError("Could not generate Idempotency-Key", err.Error())
+		return
 	}
-	if data.Website == "" {
-		plan.Website = types.StringNull()
-	} else {
-		plan.Website = types.StringValue(data.Website)
+	resp.Diagnostics.Append(setIdempotencyKey(ctx, resp.Private, idempotencyKey)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
+	created, status, diags := r.api.CreateResume(ctx, plan.toAPI(), idempotencyKey)
+	if diags.HasError() {
+		if status != 0 {
+			resp.Diagnostics.Append(explainTimeout(ctx, createTimeout, diags)...)
+			return
+		}
+
+		// The resume may have been created although the response got lost,
+		// so look it up instead of posting it again. The error may quote the
+		// resume and is only logged by the masked resume_http subsystem.
+		tflog.Warn(ctx, "Lost response while creating Resume, looking it up", map[string]interface{}{
+			"idempotency_key": idempotencyKey,
+		})
+		if lookupCtx, cancel := lookupContext(ctx); lookupCtx != nil {
+			created, _ = r.findCreatedResume(lookupCtx, idempotencyKey)
+			cancel()
+		}
+		if created == nil {
+			resp.Diagnostics.Append(explainTimeout(ctx, createTimeout, diags)...)
+			resp.Diagnostics.AddWarning(
+				"Resume May Have Been Created",
+				"The response to creating the resume was lost. Terraform keeps it as tainted together with "+
+					"the Idempotency-Key it was created with: the next refresh looks the resume up by its key, "+
+					"and the next apply deletes it if it exists before creating it again.",
+			)
+
+			// Terraform keeps the partial state of a failed create as
+			// tainted, which carries the Idempotency-Key in private.
+			plan.Id = types.StringNull()
+			if plan.PhoneNumberE164.IsUnknown() {
+				plan.PhoneNumberE164 = types.StringNull()
+			}
+			resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+			return
+		}
+	}
+
+	plan.fromAPI(created, r.defaultRegion)
+
+	resp.Diagnostics.Append(setETag(ctx, resp.Private, created.ETag)...)
+
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -199,8 +391,10 @@ func (r *resumeResource) Create(
 func (r *resumeResource) Read(
 	ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse,
 ) {
+	ctx, span := startSpan(ctx, "resume_resume.Read")
+	defer func() { endSpan(span, resp.Diagnostics) }()
+
 	var state resumeResourceModel
-	var data resumeResourceJson
 
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -208,56 +402,60 @@ func (r *resumeResource) Read(
 		return
 	}
 
-	url := fmt.Sprintf("%s/%s", resumeEndpoint, state.Id.ValueString())
-	httpResp, err := r.client.Get(ctx, url)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error reading Resume",
-			err.Error(),
-		)
-	}
-	if httpResp.StatusCode != http.StatusOK {
-		resp.Diagnostics.AddError(
-			"Error updating Resume",
-			fmt.Sprintf("Expected 200, got %d.", httpResp.StatusCode),
-		)
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultResumeTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to read HTTP response body",
-			err.Error(),
-		)
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	if state.Id.IsNull() {
+		r.readCreated(ctx, req, resp, state, readTimeout)
 		return
 	}
 
-	if err = json.Unmarshal(body, &data); err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to parse HTTP response body",
-			err.Error(),
-		)
+	etag, diags := getETag(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	state.Id = types.StringValue(strconv.FormatInt(data.Id, 10))
-	state.Name = types.StringValue(data.Name)
-	if data.Address == "" {
-		state.Address = types.StringNull()
-	} else {
-		state.Address = types.StringValue(data.Address)
+	data, status, diags := r.api.GetResume(ctx, state.Id.ValueString(), resumeapi.GetResumeOptions{IfNoneMatch: etag})
+	if status == http.StatusNotModified {
+		// The resume did not change since it was last read, keep the prior
+		// state without decoding it again.
+		tflog.Debug(ctx, "Resume not modified", map[string]interface{}{"etag": etag})
+		return
 	}
-	if data.PhoneNumber == "" {
-		state.PhoneNumber = types.StringNull()
-	} else {
-		state.PhoneNumber = types.StringValue(data.PhoneNumber)
+	if status == http.StatusNotFound || status == http.StatusGone {
+		// The resume was deleted outside of Terraform, which plans to create
+		// it again.
+		tflog.Warn(ctx, "Resume not found, removing it from the state", map[string]interface{}{
+			"id": state.Id.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
 	}
-	if data.Website == "" {
-		state.Website = types.StringNull()
-	} else {
-		state.Website = types.StringValue(data.Website)
+	resp.Diagnostics.Append(explainTimeout(ctx, readTimeout, diags)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	if data.DeletedAt != nil {
+		resp.Diagnostics.AddWarning(
+			"Resume Deleted",
+			fmt.Sprintf("The resume %s was deleted outside of Terraform at %s. "+
+				"It is removed from the state and will be created again.",
+				state.Id.ValueString(), data.DeletedAt.Format(time.RFC3339)),
+		)
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.fromAPI(data, r.defaultRegion)
+
+	resp.Diagnostics.Append(setETag(ctx, resp.Private, data.ETag)...)
 
 	diags = resp.State.Set(ctx, state)
 	resp.Diagnostics.Append(diags...)
@@ -266,9 +464,47 @@ func (r *resumeResource) Read(
 	}
 }
 
+// readCreated refreshes the tainted state left by a create whose response
+// got lost, looking up the resume by the Idempotency-Key in private. The
+// state is removed if the resume was not created.
+func (r *resumeResource) readCreated(
+	ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse, state resumeResourceModel,
+	readTimeout time.Duration,
+) {
+	key, diags := getIdempotencyKey(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var created *resumeapi.Resume
+	if key != "" {
+		created, diags = r.findCreatedResume(ctx, key)
+		resp.Diagnostics.Append(explainTimeout(ctx, readTimeout, diags)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	if created == nil {
+		tflog.Debug(ctx, "Resume was not created, removing it from the state", map[string]interface{}{
+			"idempotency_key": key,
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.fromAPI(created, r.defaultRegion)
+
+	resp.Diagnostics.Append(setETag(ctx, resp.Private, created.ETag)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
 func (r *resumeResource) Update(
 	ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse,
 ) {
+	ctx, span := startSpan(ctx, "resume_resume.Update")
+	defer func() { endSpan(span, resp.Diagnostics) }()
+
 	var plan resumeResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -276,81 +512,43 @@ func (r *resumeResource) Update(
 		return
 	}
 
-	var data resumeResourceJson
-	data.Name = plan.Name.ValueString()
-	data.Address = plan.Address.ValueString()
-	data.PhoneNumber = plan.PhoneNumber.ValueString()
-	data.Website = plan.Website.ValueString()
-
-	reqBodyBytes, err := json.Marshal(data)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Could not convert JSON to request body",
-			err.Error(),
-		)
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultResumeTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	reqBody := bytes.NewReader(reqBodyBytes)
-	if reqBody == nil {
-		resp.Diagnostics.AddError(
-			"Could not create reader from bytes",
-			err.Error(),
-		)
-		return
-	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
 
-	url := fmt.Sprintf("%s/%s", resumeEndpoint, plan.Id.ValueString())
-	httpResp, err := r.client.Patch(ctx, url, reqBody)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating Resume",
-			err.Error(),
-		)
+	var state resumeResourceModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	if httpResp.StatusCode != http.StatusOK {
-		resp.Diagnostics.AddError(
-			"Error updating Resume",
-			fmt.Sprintf("Expected 200, got %d.", httpResp.StatusCode),
-		)
+
+	opts, diags := r.writeOptions(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to read HTTP response body",
-			err.Error(),
-		)
+	// Remote changes are only overwritten if every attribute is sent.
+	patch := plan.toPatch(state, r.conflictPolicy == conflictPolicyOverwrite)
+	data, status, diags := r.api.UpdateResume(ctx, plan.Id.ValueString(), patch, opts)
+	if status == http.StatusPreconditionFailed {
+		r.addConflictError(ctx, &resp.Diagnostics, "Error updating Resume", state)
 		return
 	}
-
-	if err = json.Unmarshal(body, &data); err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to parse HTTP response body",
-			err.Error(),
-		)
+	resp.Diagnostics.Append(explainTimeout(ctx, updateTimeout, diags)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	plan.Id = types.StringValue(strconv.FormatInt(data.Id, 10))
-	plan.Name = types.StringValue(data.Name)
-	if data.Address == "" {
-		plan.Address = types.StringNull()
-	} else {
-		plan.Address = types.StringValue(data.Address)
-	}
-	if data.PhoneNumber == "" {
-		plan.PhoneNumber = types.StringNull()
-	} else {
-		plan.PhoneNumber = types.StringValue(data.PhoneNumber)
-	}
-	if data.Website == "" {
-		plan.Website = types.StringNull()
-	} else {
-		plan.Website = types.StringValue(data.Website)
-	}
+	plan.fromAPI(data, r.defaultRegion)
+
+	resp.Diagnostics.Append(setETag(ctx, resp.Private, data.ETag)...)
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -362,6 +560,9 @@ func (r *resumeResource) Update(
 func (r *resumeResource) Delete(
 	ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse,
 ) {
+	ctx, span := startSpan(ctx, "resume_resume.Delete")
+	defer func() { endSpan(span, resp.Diagnostics) }()
+
 	var state resumeResourceModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -369,20 +570,49 @@ func (r *resumeResource) Delete(
 		return
 	}
 
-	url := fmt.Sprintf("%s/%s", resumeEndpoint, state.Id.ValueString())
-	httpResp, err := r.client.Delete(ctx, url)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating Resume",
-			err.Error(),
-		)
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultResumeTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	if httpResp.StatusCode != http.StatusNoContent {
-		resp.Diagnostics.AddError(
-			"Error deleted Resume",
-			fmt.Sprintf("Expected %d, got %d.", http.StatusNoContent, httpResp.StatusCode),
-		)
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	opts, diags := r.writeOptions(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.Id.IsNull() {
+		// The tainted state of a create whose response got lost, which was
+		// not refreshed since.
+		key, diags := getIdempotencyKey(ctx, req.Private)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() || key == "" {
+			return
+		}
+
+		created, diags := r.findCreatedResume(ctx, key)
+		resp.Diagnostics.Append(explainTimeout(ctx, deleteTimeout, diags)...)
+		if resp.Diagnostics.HasError() || created == nil {
+			return
+		}
+		state.Id = types.StringValue(strconv.FormatInt(created.Id, 10))
+	}
+
+	status, diags := r.api.DeleteResume(ctx, state.Id.ValueString(), opts)
+	if status == http.StatusNotFound || status == http.StatusGone {
+		tflog.Debug(ctx, "Resume already deleted", map[string]interface{}{"id": state.Id.ValueString()})
+		return
+	}
+	if status == http.StatusPreconditionFailed {
+		r.addConflictError(ctx, &resp.Diagnostics, "Error deleting Resume", state)
+		return
+	}
+	resp.Diagnostics.Append(explainTimeout(ctx, deleteTimeout, diags)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 }
@@ -392,3 +622,281 @@ func (r *resumeResource) ImportState(
 ) {
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
+
+// toAPI returns m as sent to create a resume, null attributes are left out.
+func (m resumeResourceModel) toAPI() resumeapi.Resume {
+	return resumeapi.Resume{
+		Name:        m.Name.ValueString(),
+		Address:     m.Address.toAPI(),
+		PhoneNumber: m.PhoneNumber.ValueStringPointer(),
+		Website:     m.Website.ValueStringPointer(),
+		Emails:      emailsToAPI(m.Emails),
+		Profiles:    profilesToAPI(m.Profiles),
+	}
+}
+
+// toPatch returns the changes from prior to m. Attributes which are null in
+// both are left out unless overwrite is set, attributes which became null are
+// cleared.
+func (m resumeResourceModel) toPatch(prior resumeResourceModel, overwrite bool) resumeapi.ResumePatch {
+	return resumeapi.ResumePatch{
+		Name:        m.Name.ValueStringPointer(),
+		Address:     nullablePatch(m.Address.toAPI(), prior.Address == nil, overwrite),
+		PhoneNumber: nullablePatch(m.PhoneNumber.ValueStringPointer(), prior.PhoneNumber.IsNull(), overwrite),
+		Website:     nullablePatch(m.Website.ValueStringPointer(), prior.Website.IsNull(), overwrite),
+		Emails:      nullablePatch(emailsToAPI(m.Emails), prior.Emails == nil, overwrite),
+		Profiles:    nullablePatch(profilesToAPI(m.Profiles), prior.Profiles == nil, overwrite),
+	}
+}
+
+// nullablePatch returns the change of an attribute to planned, which is nil
+// if the attribute is null.
+func nullablePatch[T any](planned *T, priorNull, overwrite bool) *resumeapi.Nullable[T] {
+	switch {
+	case planned != nil:
+		return resumeapi.Value(*planned)
+	case !priorNull || overwrite:
+		return resumeapi.Null[T]()
+	}
+	return nil
+}
+
+// fromAPI sets m to data. Null fields are stored as null and empty strings as
+// empty strings. Phone numbers in national format are taken to be from
+// defaultRegion.
+func (m *resumeResourceModel) fromAPI(data *resumeapi.Resume, defaultRegion string) {
+	m.Id = types.StringValue(strconv.FormatInt(data.Id, 10))
+	m.Name = types.StringValue(data.Name)
+	m.Address = addressFromAPI(data.Address)
+	m.PhoneNumber = NewPhoneNumberPointerValue(data.PhoneNumber)
+	m.Website = NewURLPointerValue(data.Website)
+	m.Emails = emailsFromAPI(data.Emails)
+	m.Profiles = profilesFromAPI(data.Profiles)
+
+	m.PhoneNumberE164 = types.StringNull()
+	if data.PhoneNumber != nil {
+		if e164, err := phoneNumberE164(*data.PhoneNumber, defaultRegion); err == nil {
+			m.PhoneNumberE164 = types.StringValue(e164)
+		}
+	}
+}
+
+const (
+	// resumeETagKey is the private state key of the ETag of the resume as
+	// last read or written.
+	resumeETagKey = "etag"
+	// resumeIdempotencyKey is the private state key of the Idempotency-Key
+	// the resume was created with. Every resource instance is created with
+	// a random key, so identical resumes are never merged into one. Retries
+	// of the request and the lookups after a lost response reuse it, which
+	// the tainted state of a failed create carries across applies.
+	resumeIdempotencyKey = "idempotency_key"
+)
+
+// privateState is implemented by the private state of resource requests and
+// responses.
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// getETag returns the ETag stored in private, if any.
+func getETag(ctx context.Context, private privateState) (string, diag.Diagnostics) {
+	data, diags := private.GetKey(ctx, resumeETagKey)
+	if diags.HasError() || data == nil {
+		return "", diags
+	}
+
+	var etag string
+	if err := json.Unmarshal(data, &etag); err != nil {
+		// Ignoring a broken ETag merely costs a full read.
+		tflog.Warn(ctx, "Ignoring invalid ETag in private state", map[string]interface{}{"error": err.Error()})
+		return "", diags
+	}
+	return etag, diags
+}
+
+// setETag stores etag in private, private state values have to be JSON.
+func setETag(ctx context.Context, private privateState, etag string) diag.Diagnostics {
+	data, err := json.Marshal(etag)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Could not encode ETag", err.Error())
+		return diags
+	}
+	return private.SetKey(ctx, resumeETagKey, data)
+}
+
+// getIdempotencyKey returns the Idempotency-Key stored in private, if any.
+func getIdempotencyKey(ctx context.Context, private privateState) (string, diag.Diagnostics) {
+	data, diags := private.GetKey(ctx, resumeIdempotencyKey)
+	if diags.HasError() || data == nil {
+		return "", diags
+	}
+
+	var key string
+	if err := json.Unmarshal(data, &key); err != nil {
+		diags.AddError("Could not decode Idempotency-Key", err.Error())
+	}
+	return key, diags
+}
+
+// setIdempotencyKey stores key in private.
+func setIdempotencyKey(ctx context.Context, private privateState, key string) diag.Diagnostics {
+	data, err := json.Marshal(key)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Could not encode Idempotency-Key", err.Error())
+		return diags
+	}
+	return private.SetKey(ctx, resumeIdempotencyKey, data)
+}
+
+// writeOptions makes updates and deletes conditional on the ETag stored in
+// private, unless remote changes are to be overwritten. If-Match requires a
+// strong ETag, so the change fails if the API only issues weak ones.
+func (r *resumeResource) writeOptions(
+	ctx context.Context, private privateState,
+) (resumeapi.WriteOptions, diag.Diagnostics) {
+	if r.conflictPolicy == conflictPolicyOverwrite {
+		return resumeapi.WriteOptions{}, nil
+	}
+
+	etag, diags := getETag(ctx, private)
+	if strings.HasPrefix(etag, "W/") {
+		diags.AddError(
+			"Remote Changes Cannot Be Detected",
+			fmt.Sprintf("The Resume API issued the weak ETag %s, which cannot be used to detect changes made "+
+				"outside of Terraform. Configure the API to issue strong ETags, or set "+
+				"conflict_policy = \"%s\" in the provider configuration to send changes unconditionally.",
+				etag, conflictPolicyOverwrite),
+		)
+		return resumeapi.WriteOptions{}, diags
+	}
+	return resumeapi.WriteOptions{IfMatch: etag}, diags
+}
+
+// addConflictError adds an error for a resume which was changed remotely
+// since state was read, listing the fields that changed.
+func (r *resumeResource) addConflictError(
+	ctx context.Context, diags *diag.Diagnostics, summary string, state resumeResourceModel,
+) {
+	detail := "The resume was changed outside of Terraform since it was last read."
+
+	remote, _, remoteDiags := r.api.GetResume(ctx, state.Id.ValueString(), resumeapi.GetResumeOptions{})
+	if remoteDiags.HasError() {
+		// The error may quote the resume and is only logged by the masked
+		// resume_http subsystem.
+		tflog.Warn(ctx, "Could not read the conflicting Resume")
+	} else if fields := changedResumeFields(state.toAPI(), *remote, "id"); len(fields) > 0 {
+		detail += fmt.Sprintf(" Fields changed remotely: %s.", strings.Join(fields, ", "))
+	}
+
+	detail += "\n\nRefresh the state and review the changes before applying again, " +
+		"or set conflict_policy = \"overwrite\" in the provider configuration to replace remote changes."
+	diags.AddError(summary, detail)
+}
+
+// changedResumeFields returns the sorted API names of the fields which
+// differ between a and b, apart from ignored.
+func changedResumeFields(a, b resumeapi.Resume, ignored ...string) []string {
+	fieldsA, fieldsB := resumeFields(a), resumeFields(b)
+	for _, field := range ignored {
+		delete(fieldsA, field)
+		delete(fieldsB, field)
+	}
+
+	var changed []string
+	for field, value := range fieldsA {
+		if !bytes.Equal(value, fieldsB[field]) {
+			changed = append(changed, field)
+		}
+	}
+	// Null fields are left out of the encoding.
+	for field := range fieldsB {
+		if _, ok := fieldsA[field]; !ok {
+			changed = append(changed, field)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// resumeFields returns the JSON encoded fields of resume keyed by API name.
+func resumeFields(resume resumeapi.Resume) map[string]json.RawMessage {
+	// Encoding the plain struct cannot fail.
+	data, _ := json.Marshal(resume)
+
+	var fields map[string]json.RawMessage
+	_ = json.Unmarshal(data, &fields)
+	return fields
+}
+
+// findCreatedResume looks up the resume created with the Idempotency-Key
+// key, it is nil if there is none.
+func (r *resumeResource) findCreatedResume(
+	ctx context.Context, key string,
+) (*resumeapi.Resume, diag.Diagnostics) {
+	var created *resumeapi.Resume
+	var diags diag.Diagnostics
+
+	opts := resumeapi.ListResumesOptions{IdempotencyKey: key, Limit: 1}
+	r.api.ListResumes(ctx, opts)(func(resume resumeapi.Resume, listDiags diag.Diagnostics) bool {
+		diags.Append(listDiags...)
+		if !listDiags.HasError() && resume.DeletedAt == nil {
+			created = &resume
+		}
+		return false
+	})
+	return created, diags
+}
+
+// resumeLookupTimeout bounds looking up a resume after its create timed out.
+const resumeLookupTimeout = 30 * time.Second
+
+// lookupContext returns the context to look up a resume after its create
+// failed on ctx. Once ctx expired, the lookup gets a context of its own
+// bounded by resumeLookupTimeout. The context is nil if ctx was canceled.
+func lookupContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return context.WithTimeout(detachedContext{ctx}, resumeLookupTimeout)
+	case ctx.Err() != nil:
+		return nil, nil
+	default:
+		return ctx, func() {}
+	}
+}
+
+// detachedContext keeps the values of a context, e.g. its loggers, but
+// neither its deadline nor its cancellation.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+// explainTimeout returns diags with their errors explaining that the
+// operation did not complete within timeout, which bounds ctx, if ctx
+// expired.
+func explainTimeout(ctx context.Context, timeout time.Duration, diags diag.Diagnostics) diag.Diagnostics {
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return diags
+	}
+
+	explained := make(diag.Diagnostics, 0, len(diags))
+	for _, d := range diags {
+		if d.Severity() != diag.SeverityError {
+			explained = append(explained, d)
+			continue
+		}
+		explained.AddError(d.Summary(), fmt.Sprintf(
+			"The operation did not complete within its timeout of %s: %s\n\n"+
+				"The timeout can be increased in the timeouts block of the resource.",
+			timeout, d.Detail(),
+		))
+	}
+	return explained
+}
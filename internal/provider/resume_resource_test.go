@@ -1,7 +1,21 @@
 package provider
 
 import (
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 )
 
@@ -12,7 +26,7 @@ var (
 func TestAccResumeResource(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(t),
 		Steps: []resource.TestStep{
 			// Create and Read
 			{
@@ -27,6 +41,8 @@ resource "resume_resume" "test" {
 					resource.TestCheckNoResourceAttr(resourceName, "address"),
 					resource.TestCheckNoResourceAttr(resourceName, "phone_number"),
 					resource.TestCheckNoResourceAttr(resourceName, "website"),
+					resource.TestCheckNoResourceAttr(resourceName, "emails"),
+					resource.TestCheckNoResourceAttr(resourceName, "profiles"),
 				),
 			},
 			// Import state
@@ -40,18 +56,812 @@ resource "resume_resume" "test" {
 				Config: providerConfig + `
 resource "resume_resume" "test" {
 	name = "TJ McTester"
-	address = "1 Test Lane"
-	phone_number = "555-555-5555"
+	address = {
+		street_lines = ["1 Test Lane"]
+		city         = "Scranton"
+		country_code = "US"
+	}
+	phone_number = "+1 555-555-5555"
 	website = "https://test.com"
+	emails = [
+		{
+			address = "tj@test.com"
+			primary = true
+		},
+		{
+			address = "tj@work.test.com"
+			label   = "work"
+		},
+	]
+	profiles = [
+		{
+			network  = "GitHub"
+			username = "tjmctester"
+			url      = "https://github.com/tjmctester"
+		},
+	]
 }
 `,
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr(resourceName, "name", "TJ McTester"),
-					resource.TestCheckResourceAttr(resourceName, "address", "1 Test Lane"),
-					resource.TestCheckResourceAttr(resourceName, "phone_number", "555-555-5555"),
+					resource.TestCheckResourceAttr(resourceName, "address.street_lines.0", "1 Test Lane"),
+					resource.TestCheckResourceAttr(resourceName, "address.city", "Scranton"),
+					resource.TestCheckResourceAttr(resourceName, "address.country_code", "US"),
+					resource.TestCheckResourceAttr(resourceName, "phone_number", "+1 555-555-5555"),
 					resource.TestCheckResourceAttr(resourceName, "website", "https://test.com"),
+					resource.TestCheckResourceAttr(resourceName, "emails.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "emails.0.address", "tj@test.com"),
+					resource.TestCheckResourceAttr(resourceName, "emails.0.primary", "true"),
+					resource.TestCheckResourceAttr(resourceName, "emails.1.label", "work"),
+					resource.TestCheckResourceAttr(resourceName, "emails.1.primary", "false"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "profiles.*", map[string]string{
+						"network":  "GitHub",
+						"username": "tjmctester",
+						"url":      "https://github.com/tjmctester",
+					}),
 				),
 			},
 		},
 	})
 }
+
+// TestAccResumeResourceEmailsAndProfiles runs against the fake API, which
+// normalizes profile URLs, so it needs no cassette.
+func TestAccResumeResourceEmailsAndProfiles(t *testing.T) {
+	api := newFakeAPI(t)
+	api.normalizeURLs = true
+
+	config := fmt.Sprintf(providerConfigTemplate, api.URL, fakeAPIToken) + `
+resource "resume_resume" "test" {
+	name = "TJ McTester"
+	emails = [
+		{
+			address = "tj@test.com"
+			primary = true
+		},
+		{
+			address = "tj@work.test.com"
+			label   = "work"
+		},
+	]
+	profiles = [
+		{
+			network  = "GitHub"
+			username = "tjmctester"
+			url      = "HTTPS://GitHub.com/tjmctester/"
+		},
+		{
+			network  = "Mastodon"
+			username = "@tjmctester@test.social"
+		},
+	]
+}
+`
+	check := resource.ComposeAggregateTestCheckFunc(
+		resource.TestCheckResourceAttr(resourceName, "emails.#", "2"),
+		resource.TestCheckResourceAttr(resourceName, "emails.0.address", "tj@test.com"),
+		resource.TestCheckResourceAttr(resourceName, "emails.0.primary", "true"),
+		resource.TestCheckNoResourceAttr(resourceName, "emails.0.label"),
+		resource.TestCheckResourceAttr(resourceName, "emails.1.address", "tj@work.test.com"),
+		resource.TestCheckResourceAttr(resourceName, "emails.1.label", "work"),
+		resource.TestCheckResourceAttr(resourceName, "emails.1.primary", "false"),
+		resource.TestCheckResourceAttr(resourceName, "profiles.#", "2"),
+		resource.TestCheckTypeSetElemNestedAttrs(resourceName, "profiles.*", map[string]string{
+			"network":  "GitHub",
+			"username": "tjmctester",
+		}),
+		resource.TestCheckTypeSetElemNestedAttrs(resourceName, "profiles.*", map[string]string{
+			"network":  "Mastodon",
+			"username": "@tjmctester@test.social",
+		}),
+		func(*terraform.State) error {
+			stored, _ := api.field(1, "profiles")
+			profiles, _ := stored.([]interface{})
+			for _, profile := range profiles {
+				if profile, _ := profile.(map[string]interface{}); profile["network"] == "GitHub" &&
+					profile["url"] != "https://github.com/tjmctester" {
+					return fmt.Errorf("expected the API to normalize the profile URL, got %v", profile["url"])
+				}
+			}
+			return nil
+		},
+	)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"resume": providerserver.NewProtocol6WithError(&ResumeProvider{version: "test"}),
+		},
+		Steps: []resource.TestStep{
+			// Create and Read, the normalized URL must not show up as a
+			// change in the plan after the apply.
+			{
+				Config: config,
+				Check:  check,
+			},
+			// Refresh
+			{
+				RefreshState: true,
+				Check:        check,
+			},
+			// The configured URL still plans no change after a refresh.
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+			// Import state, which has the URL as normalized by the API.
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"profiles.0.url", "profiles.1.url"},
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					for key, value := range states[0].Attributes {
+						if strings.HasPrefix(key, "profiles.") && strings.HasSuffix(key, ".url") &&
+							value != "https://github.com/tjmctester" {
+							return fmt.Errorf("expected the imported %s to be normalized, got %s", key, value)
+						}
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestResumeResourceCreateIdempotent(t *testing.T) {
+	plan := testResumePlan("Michael G Scott")
+	plan.PhoneNumber = NewPhoneNumberValue("555-555-5555")
+
+	t.Run("retried create", func(t *testing.T) {
+		api := newFakeAPI(t)
+		api.dropCreateResponses = 1
+		p := api.provider(api.config())
+
+		state, _, diags := p.applyResume(nil, &plan, nil)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+
+		if state.Id.ValueString() != "1" || api.Resumes() != 1 {
+			t.Errorf("expected a single resume with ID 1, got ID %s and %d resumes", state.Id, api.Resumes())
+		}
+	})
+
+	t.Run("lost response", func(t *testing.T) {
+		api := newFakeAPI(t)
+		api.dropCreateResponses = 10
+		config := api.config()
+		config.MaxRetries = types.Int64Value(1)
+		// The transport retries requests with an Idempotency-Key on
+		// its own if they fail on a connection kept alive from the
+		// preflight, which would add another create.
+		config.SkipPreflight = types.BoolValue(true)
+		p := api.provider(config)
+
+		state, _, diags := p.applyResume(nil, &plan, nil)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+
+		if state.Id.ValueString() != "1" || api.Resumes() != 1 {
+			t.Errorf("expected a single resume with ID 1, got ID %s and %d resumes", state.Id, api.Resumes())
+		}
+		expected := []string{"POST /resumes", "POST /resumes", "GET /resumes"}
+		if requests := api.Requests(); !reflect.DeepEqual(requests, expected) {
+			t.Errorf("expected requests %v, got %v", expected, requests)
+		}
+	})
+
+	t.Run("timed out", func(t *testing.T) {
+		api := newFakeAPI(t)
+		api.stallCreateResponses = true
+		p := api.provider(api.config())
+
+		planned := plan
+		planned.Timeouts = testResumeTimeouts(map[string]string{"create": "50ms"})
+		state, _, diags := p.applyResume(nil, &planned, nil)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+
+		if state.Id.ValueString() != "1" || api.Resumes() != 1 {
+			t.Errorf("expected a single resume with ID 1, got ID %s and %d resumes", state.Id, api.Resumes())
+		}
+		// The lookup runs after the create timeout expired.
+		if requests := api.Requests(); requests[len(requests)-1] != "GET /resumes" {
+			t.Errorf("expected the resume to be looked up, got requests %v", requests)
+		}
+	})
+
+	t.Run("lost response and lookup", func(t *testing.T) {
+		api := newFakeAPI(t)
+		api.dropCreateResponses = 10
+		api.failLists = 10
+		config := api.config()
+		config.MaxRetries = types.Int64Value(1)
+		config.SkipPreflight = types.BoolValue(true)
+		p := api.provider(config)
+
+		// Terraform keeps the partial state as tainted.
+		partial, private, diags := p.applyResume(nil, &plan, nil)
+		if diags.ErrorsCount() != 1 || diags[0].Summary() != "Error creating Resume" {
+			t.Fatalf("expected a create error, got %v", diags)
+		}
+		if !partial.Id.IsNull() || partial.Name.ValueString() != "Michael G Scott" {
+			t.Fatalf("expected a partial state without ID, got %+v", partial)
+		}
+		api.failLists = 0
+
+		refreshed, _, diags := p.readResume(partial, private)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		if refreshed.Id.ValueString() != "1" {
+			t.Errorf("expected the refresh to find the resume with ID 1, got %s", refreshed.Id)
+		}
+
+		// Replacing the tainted resume deletes it first, even without a
+		// refresh.
+		if _, _, diags := p.applyResume(&partial, nil, private); diags.HasError() {
+			t.Fatal(diags)
+		}
+		if api.Resumes() != 0 {
+			t.Errorf("expected the resume to be deleted, got %d resumes", api.Resumes())
+		}
+
+		removed, _, diags := p.readResume(partial, private)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		if !removed.Id.IsNull() || removed.Name.ValueString() != "" {
+			t.Errorf("expected the state to be removed once the resume is gone, got %+v", removed)
+		}
+	})
+}
+
+func TestResumeResourceCreateIdentical(t *testing.T) {
+	api := newFakeAPI(t)
+	p := api.provider(api.config())
+
+	// Like resources created with count or for_each.
+	plan := testResumePlan("Michael G Scott")
+	first, firstPrivate, diags := p.applyResume(nil, &plan, nil)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	second, secondPrivate, diags := p.applyResume(nil, &plan, nil)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	if first.Id == second.Id || api.Resumes() != 2 {
+		t.Errorf("expected two resumes, got IDs %s and %s", first.Id, second.Id)
+	}
+
+	var keys []string
+	for _, private := range [][]byte{firstPrivate, secondPrivate} {
+		// Private state maps keys to the JSON values set by the provider.
+		var data map[string][]byte
+		var key string
+		if err := json.Unmarshal(private, &data); err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(data[resumeIdempotencyKey], &key); err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+	}
+	if keys[0] == "" || keys[0] == keys[1] {
+		t.Errorf("expected distinct idempotency keys in private state, got %q", keys)
+	}
+}
+
+func TestResumeResourceCreateValidationError(t *testing.T) {
+	api := newFakeAPI(t)
+	p := api.provider(api.config())
+
+	plan := testResumePlan("")
+	_, _, diags := p.applyResume(nil, &plan, nil)
+
+	if diags.ErrorsCount() != 1 {
+		t.Fatalf("expected 1 error, got %v", diags)
+	}
+	withPath, ok := diags[0].(diag.DiagnosticWithPath)
+	if !ok || !withPath.Path().Equal(path.Root("name")) {
+		t.Errorf("expected error for name, got %v", diags[0])
+	}
+}
+
+func TestResumeResourceReadNotModified(t *testing.T) {
+	api := newFakeAPI(t)
+	p := api.provider(api.config())
+
+	plan := testResumePlan("Michael G Scott")
+	created, private, diags := p.applyResume(nil, &plan, nil)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if string(private) == "" {
+		t.Fatal("expected ETag to be stored in private state")
+	}
+
+	// Change the stored resume behind the back of the fake API, so only
+	// decoding the response would pick it up.
+	api.mu.Lock()
+	api.resumes[1]["address"] = map[string]interface{}{"city": "Scranton"}
+	api.mu.Unlock()
+
+	state, newPrivate, diags := p.readResume(created, private)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if !reflect.DeepEqual(state, created) {
+		t.Errorf("expected state to be kept on 304 Not Modified, got %+v", state)
+	}
+	if string(newPrivate) != string(private) {
+		t.Errorf("expected private state to be kept, got %s", newPrivate)
+	}
+
+	// A new version of the resume is decoded.
+	api.mu.Lock()
+	api.versions[1]++
+	api.mu.Unlock()
+
+	state, newPrivate, diags = p.readResume(state, newPrivate)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if state.Address == nil || state.Address.City.ValueString() != "Scranton" {
+		t.Errorf("expected modified resume to be read, got %+v", state)
+	}
+	if string(newPrivate) == string(private) {
+		t.Error("expected new ETag to be stored in private state")
+	}
+
+	// Without an ETag, e.g. after an import, the resume is always read.
+	api.mu.Lock()
+	api.resumes[1]["address"] = map[string]interface{}{"city": "Stamford"}
+	api.mu.Unlock()
+
+	state, _, diags = p.readResume(state, nil)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if state.Address == nil || state.Address.City.ValueString() != "Stamford" {
+		t.Errorf("expected resume to be read without an ETag, got %+v", state)
+	}
+}
+
+func TestResumeResourceReadDeleted(t *testing.T) {
+	cases := map[string]struct {
+		delete   func(api *fakeAPI)
+		warnings int
+	}{
+		"not found": {delete: func(api *fakeAPI) { api.remove(1, false) }},
+		"gone":      {delete: func(api *fakeAPI) { api.remove(1, true) }},
+		"soft deleted": {
+			delete:   func(api *fakeAPI) { api.change(1, "deleted_at", "2023-07-01T12:00:00Z") },
+			warnings: 1,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			p := api.provider(api.config())
+
+			plan := testResumePlan("Michael G Scott")
+			created, private, diags := p.applyResume(nil, &plan, nil)
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+
+			c.delete(api)
+
+			state, _, diags := p.readResume(created, private)
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+			if !state.Id.IsNull() {
+				t.Errorf("expected the resume to be removed from the state, got %+v", state)
+			}
+
+			warnings := diags.Warnings()
+			if len(warnings) != c.warnings {
+				t.Fatalf("expected %d warnings, got %v", c.warnings, warnings)
+			}
+			for _, w := range warnings {
+				if w.Summary() != "Resume Deleted" || !strings.Contains(w.Detail(), "2023-07-01T12:00:00Z") {
+					t.Errorf("unexpected warning %q: %s", w.Summary(), w.Detail())
+				}
+			}
+		})
+	}
+}
+
+func TestResumeResourceDeleteDeleted(t *testing.T) {
+	for name, purged := range map[string]bool{"not found": false, "gone": true} {
+		t.Run(name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			p := api.provider(api.config())
+
+			plan := testResumePlan("Michael G Scott")
+			created, private, diags := p.applyResume(nil, &plan, nil)
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+
+			api.remove(1, purged)
+
+			if _, _, diags := p.applyResume(&created, nil, private); diags.HasError() {
+				t.Fatalf("expected deleting a deleted resume to succeed, got %v", diags)
+			}
+		})
+	}
+}
+
+func TestResumeResourceNullableFields(t *testing.T) {
+	null, empty := NewURLNull(), NewURLValue("")
+	website, other := NewURLValue("https://michaelthesco.tt"), NewURLValue("https://dundermifflin.com")
+
+	// omitted means the field was never sent to the API, nil that it was
+	// cleared.
+	const omitted = "omitted"
+	stored := func(value URL) interface{} {
+		if value.IsNull() {
+			return nil
+		}
+		return value.ValueString()
+	}
+
+	tests := map[string]struct {
+		prior, planned URL
+		// expected is the stored value after the update.
+		expected interface{}
+	}{
+		"null to null":   {prior: null, planned: null, expected: omitted},
+		"null to empty":  {prior: null, planned: empty, expected: ""},
+		"null to value":  {prior: null, planned: website, expected: website.ValueString()},
+		"empty to null":  {prior: empty, planned: null, expected: nil},
+		"empty to empty": {prior: empty, planned: empty, expected: ""},
+		"empty to value": {prior: empty, planned: website, expected: website.ValueString()},
+		"value to null":  {prior: website, planned: null, expected: nil},
+		"value to empty": {prior: website, planned: empty, expected: ""},
+		"value to value": {prior: website, planned: other, expected: other.ValueString()},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			p := api.provider(api.config())
+
+			plan := testResumePlan("Michael G Scott")
+			plan.Website = test.prior
+			created, private, diags := p.applyResume(nil, &plan, nil)
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+			if !created.Website.Equal(test.prior) {
+				t.Errorf("expected created website %s, got %s", test.prior, created.Website)
+			}
+			if value, ok := api.field(1, "website"); ok == test.prior.IsNull() || value != stored(test.prior) {
+				t.Errorf("expected created website to be stored as %#v, got %#v", stored(test.prior), value)
+			}
+
+			planned := created
+			planned.Name = types.StringValue("Michael Scott")
+			planned.Website = test.planned
+			updated, private, diags := p.applyResume(&created, &planned, private)
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+			if !updated.Website.Equal(test.planned) {
+				t.Errorf("expected updated website %s, got %s", test.planned, updated.Website)
+			}
+
+			value, ok := api.field(1, "website")
+			if test.expected == omitted {
+				if ok {
+					t.Errorf("expected website to be left out of the update, got %#v", value)
+				}
+			} else if !ok || value != test.expected {
+				t.Errorf("expected website to be stored as %#v, got %#v", test.expected, value)
+			}
+
+			// The stored value round-trips through a refresh.
+			api.change(1, "name", "Michael Scott")
+			refreshed, _, diags := p.readResume(updated, private)
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+			if !refreshed.Website.Equal(test.planned) {
+				t.Errorf("expected refreshed website %s, got %s", test.planned, refreshed.Website)
+			}
+		})
+	}
+}
+
+func TestResumeResourceConflict(t *testing.T) {
+	api := newFakeAPI(t)
+	p := api.provider(api.config())
+
+	plan := testResumePlan("Michael G Scott")
+	created, private, diags := p.applyResume(nil, &plan, nil)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	api.change(1, "address", map[string]interface{}{"city": "Scranton"})
+	api.change(1, "website", "https://dundermifflin.com")
+
+	planned := created
+	planned.Name = types.StringValue("Michael Scott")
+
+	_, _, diags = p.applyResume(&created, &planned, private)
+	if diags.ErrorsCount() != 1 {
+		t.Fatalf("expected a conflict error, got %v", diags)
+	}
+	if summary := diags[0].Summary(); summary != "Error updating Resume" {
+		t.Errorf("expected update error, got %q", summary)
+	}
+	if detail := diags[0].Detail(); !strings.Contains(detail, "Fields changed remotely: address, website.") {
+		t.Errorf("expected changed fields to be listed, got %q", detail)
+	}
+
+	_, _, diags = p.applyResume(&created, nil, private)
+	if diags.ErrorsCount() != 1 || diags[0].Summary() != "Error deleting Resume" {
+		t.Fatalf("expected a conflict error, got %v", diags)
+	}
+	if api.Resumes() != 1 {
+		t.Fatal("expected conflicting delete to be rejected")
+	}
+
+	// Refreshing picks up the remote changes, after which the update is
+	// accepted.
+	refreshed, private, diags := p.readResume(created, private)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	planned = refreshed
+	planned.Name = types.StringValue("Michael Scott")
+
+	updated, _, diags := p.applyResume(&refreshed, &planned, private)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if updated.Name.ValueString() != "Michael Scott" || updated.Address == nil || updated.Address.City.ValueString() != "Scranton" {
+		t.Errorf("expected update on top of remote changes, got %+v", updated)
+	}
+}
+
+func TestResumeResourceConflictOverwrite(t *testing.T) {
+	api := newFakeAPI(t)
+	config := api.config()
+	config.ConflictPolicy = types.StringValue(conflictPolicyOverwrite)
+	p := api.provider(config)
+
+	plan := testResumePlan("Michael G Scott")
+	created, private, diags := p.applyResume(nil, &plan, nil)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	api.change(1, "address", map[string]interface{}{"city": "Scranton"})
+
+	planned := created
+	planned.Name = types.StringValue("Michael Scott")
+
+	updated, _, diags := p.applyResume(&created, &planned, private)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if updated.Address != nil {
+		t.Errorf("expected remote change to be overwritten, got %+v", updated)
+	}
+
+	api.change(1, "address", map[string]interface{}{"city": "Scranton"})
+
+	if _, _, diags := p.applyResume(&updated, nil, private); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if api.Resumes() != 0 {
+		t.Error("expected resume to be deleted")
+	}
+}
+
+func TestResumeResourceWeakETags(t *testing.T) {
+	api := newFakeAPI(t)
+	api.weakETags = true
+	p := api.provider(api.config())
+
+	plan := testResumePlan("Michael G Scott")
+	created, private, diags := p.applyResume(nil, &plan, nil)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	planned := created
+	planned.Name = types.StringValue("Michael Scott")
+
+	requests := len(api.Requests())
+	if _, _, diags := p.applyResume(&created, &planned, private); diags.ErrorsCount() != 1 ||
+		diags[0].Summary() != "Remote Changes Cannot Be Detected" {
+		t.Fatalf("expected an error about weak ETags, got %v", diags)
+	}
+	if _, _, diags := p.applyResume(&created, nil, private); diags.ErrorsCount() != 1 ||
+		diags[0].Summary() != "Remote Changes Cannot Be Detected" {
+		t.Fatalf("expected an error about weak ETags, got %v", diags)
+	}
+	if sent := api.Requests()[requests:]; len(sent) != 0 {
+		t.Errorf("expected no changes to be sent, got %v", sent)
+	}
+
+	config := api.config()
+	config.ConflictPolicy = types.StringValue(conflictPolicyOverwrite)
+	p = api.provider(config)
+
+	updated, private, diags := p.applyResume(&created, &planned, private)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if ifMatch := api.Header().Get("If-Match"); ifMatch != "" {
+		t.Errorf("expected an unconditional update, got If-Match %q", ifMatch)
+	}
+	if updated.Name.ValueString() != "Michael Scott" {
+		t.Errorf("expected name to be updated, got %s", updated.Name)
+	}
+
+	if _, _, diags := p.applyResume(&updated, nil, private); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if api.Resumes() != 0 {
+		t.Error("expected resume to be deleted")
+	}
+}
+
+func TestProviderConflictPolicyInvalid(t *testing.T) {
+	api := newFakeAPI(t)
+	config := api.config()
+	config.ConflictPolicy = types.StringValue("merge")
+
+	_, diags := newTestProvider(t, config)
+	if diags.ErrorsCount() != 1 {
+		t.Fatalf("expected 1 error, got %v", diags)
+	}
+	withPath, ok := diags[0].(diag.DiagnosticWithPath)
+	if !ok || !withPath.Path().Equal(path.Root("conflict_policy")) {
+		t.Errorf("expected error for conflict_policy, got %v", diags[0])
+	}
+}
+
+func TestResumeResourceTimeouts(t *testing.T) {
+	tests := map[string]struct {
+		requestTimeout string
+		timeouts       map[string]string
+		detail         string
+	}{
+		"operation timeout": {
+			requestTimeout: "0s",
+			timeouts:       map[string]string{"read": "50ms"},
+			detail:         "The operation did not complete within its timeout of 50ms",
+		},
+		"request timeout": {
+			requestTimeout: "20ms",
+			detail:         "The Resume API did not respond in time: GET /resumes/1 timed out after 20ms",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			config := api.config()
+			config.RequestTimeout = types.StringValue(test.requestTimeout)
+			p := api.provider(config)
+
+			plan := testResumePlan("Michael G Scott")
+			plan.Timeouts = testResumeTimeouts(test.timeouts)
+			created, private, diags := p.applyResume(nil, &plan, nil)
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+
+			api.stalled.Store(true)
+
+			_, _, diags = p.readResume(created, private)
+			if diags.ErrorsCount() != 1 {
+				t.Fatalf("expected a timeout error, got %v", diags)
+			}
+			if summary := diags[0].Summary(); summary != "Error reading Resume" {
+				t.Errorf("expected read error, got %q", summary)
+			}
+			if detail := diags[0].Detail(); !strings.HasPrefix(detail, test.detail) {
+				t.Errorf("expected detail starting with %q, got %q", test.detail, detail)
+			}
+		})
+	}
+}
+
+// countingTransport counts the response bodies it returns and how many of
+// them were closed.
+type countingTransport struct {
+	next           http.RoundTripper
+	opened, closed atomic.Int64
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	t.opened.Add(1)
+	resp.Body = &countingBody{ReadCloser: resp.Body, closed: &t.closed}
+	return resp, nil
+}
+
+type countingBody struct {
+	io.ReadCloser
+	closed *atomic.Int64
+	once   sync.Once
+}
+
+func (b *countingBody) Close() error {
+	b.once.Do(func() { b.closed.Add(1) })
+	return b.ReadCloser.Close()
+}
+
+func TestResumeResourceClosesBodies(t *testing.T) {
+	transport := &countingTransport{next: http.DefaultTransport}
+	api := newFakeAPI(t)
+	p, diags := newTestProviderWith(t, &ResumeProvider{
+		version:       "test",
+		wrapTransport: func(http.RoundTripper) http.RoundTripper { return transport },
+	}, api.config())
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	// Every kind of response: created, not modified, conflicts, validation
+	// errors, updated and deleted.
+	invalid := testResumePlan("")
+	if _, _, diags := p.applyResume(nil, &invalid, nil); !diags.HasError() {
+		t.Fatal("expected a validation error")
+	}
+
+	plan := testResumePlan("Michael G Scott")
+	created, private, diags := p.applyResume(nil, &plan, nil)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if _, _, diags := p.readResume(created, private); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	api.change(1, "address", map[string]interface{}{"city": "Scranton"})
+	planned := created
+	planned.Name = types.StringValue("Michael Scott")
+	if _, _, diags := p.applyResume(&created, &planned, private); !diags.HasError() {
+		t.Fatal("expected a conflict")
+	}
+
+	refreshed, private, diags := p.readResume(created, private)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	planned = refreshed
+	planned.Name = types.StringValue("Michael Scott")
+	updated, private, diags := p.applyResume(&refreshed, &planned, private)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if _, _, diags := p.applyResume(&updated, nil, private); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	opened, closed := transport.opened.Load(), transport.closed.Load()
+	if opened != int64(len(api.Requests())) {
+		t.Errorf("expected %d responses, got %d", len(api.Requests()), opened)
+	}
+	if closed != opened {
+		t.Errorf("expected every response body to be closed, %d of %d are open", opened-closed, opened)
+	}
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// resumeResourceModelV0 is the model of the resume_resume schema version 0,
+// which had the address as free text.
+type resumeResourceModelV0 struct {
+	Id          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Address     types.String `tfsdk:"address"`
+	PhoneNumber types.String `tfsdk:"phone_number"`
+	Website     types.String `tfsdk:"website"`
+}
+
+// UpgradeState upgrades the state of version 0, which stored the address as
+// free text. The address is parsed into a structured one, see parseAddress,
+// phone_number_e164 is computed from phone_number and timeouts is null. As
+// version 0 stored no ETag, the next refresh reads the resume unconditionally
+// and replaces the parsed address with the one stored by the API.
+func (r *resumeResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						Computed: true,
+					},
+					"name": schema.StringAttribute{
+						Required: true,
+					},
+					"address": schema.StringAttribute{
+						Optional: true,
+					},
+					"phone_number": schema.StringAttribute{
+						Optional: true,
+					},
+					"website": schema.StringAttribute{
+						Optional: true,
+					},
+				},
+			},
+			StateUpgrader: r.upgradeStateV0,
+		},
+	}
+}
+
+func (r *resumeResource) upgradeStateV0(
+	ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse,
+) {
+	var prior resumeResourceModelV0
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Version 0 had no timeouts block.
+	timeoutsType, _ := timeouts.BlockAll(ctx).Type().(timeouts.Type)
+
+	state := resumeResourceModel{
+		Id:              prior.Id,
+		Name:            prior.Name,
+		PhoneNumber:     PhoneNumber{StringValue: prior.PhoneNumber},
+		Website:         URL{StringValue: prior.Website},
+		PhoneNumberE164: types.StringNull(),
+		Timeouts:        timeouts.Value{Object: types.ObjectNull(timeoutsType.AttrTypes)},
+	}
+	if !prior.Address.IsNull() {
+		state.Address = parseAddress(prior.Address.ValueString())
+	}
+	if !prior.PhoneNumber.IsNull() {
+		if e164, err := phoneNumberE164(prior.PhoneNumber.ValueString(), r.defaultRegion); err == nil {
+			state.PhoneNumberE164 = types.StringValue(e164)
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
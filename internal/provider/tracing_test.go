@@ -0,0 +1,163 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// testTracing installs a global tracer provider recording into the returned
+// exporter for the duration of the test.
+func testTracing(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+
+	return exporter
+}
+
+func findSpan(spans tracetest.SpanStubs, name string) *tracetest.SpanStub {
+	for i := range spans {
+		if spans[i].Name == name {
+			return &spans[i]
+		}
+	}
+	return nil
+}
+
+func TestClientTracing(t *testing.T) {
+	exporter := testTracing(t)
+
+	var traceparents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("Traceparent"))
+		if len(traceparents) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+	resp, err := newRetryingClient(server.URL, 1).Get(ctx, "/info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	parent.End()
+
+	span := findSpan(exporter.GetSpans(), "HTTP GET")
+	if span == nil {
+		t.Fatalf("expected HTTP GET span, got %v", exporter.GetSpans())
+	}
+	if span.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Error("expected HTTP GET span to be a child of the calling span")
+	}
+	if span.SpanKind != trace.SpanKindClient {
+		t.Errorf("expected client span, got %v", span.SpanKind)
+	}
+
+	attrs := map[string]interface{}{}
+	for _, attr := range span.Attributes {
+		attrs[string(attr.Key)] = attr.Value.AsInterface()
+	}
+	expected := map[string]interface{}{
+		"http.request.method":       "GET",
+		"url.path":                  "/info",
+		"http.response.status_code": int64(http.StatusOK),
+		"http.resend_count":         int64(1),
+	}
+	for key, value := range expected {
+		if attrs[key] != value {
+			t.Errorf("expected %s %v, got %v", key, value, attrs[key])
+		}
+	}
+
+	for _, traceparent := range traceparents {
+		if !strings.Contains(traceparent, span.SpanContext.TraceID().String()+"-"+span.SpanContext.SpanID().String()) {
+			t.Errorf("expected traceparent of HTTP GET span, got %q", traceparent)
+		}
+	}
+}
+
+func TestResumeResourceTracing(t *testing.T) {
+	exporter := testTracing(t)
+	api := newFakeAPI(t)
+	p := api.provider(api.config())
+
+	plan := testResumePlan("Michael G Scott")
+	if _, _, diags := p.applyResume(nil, &plan, nil); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	plan.Name = types.StringValue("")
+	if _, _, diags := p.applyResume(nil, &plan, nil); !diags.HasError() {
+		t.Fatal("expected validation error")
+	}
+
+	var creates []tracetest.SpanStub
+	var posts []tracetest.SpanStub
+	for _, span := range exporter.GetSpans() {
+		switch span.Name {
+		case "resume_resume.Create":
+			creates = append(creates, span)
+		case "HTTP POST":
+			posts = append(posts, span)
+		}
+	}
+	if len(creates) != 2 || len(posts) != 2 {
+		t.Fatalf("expected 2 create and 2 POST spans, got %d and %d", len(creates), len(posts))
+	}
+
+	for i := range creates {
+		if posts[i].Parent.SpanID() != creates[i].SpanContext.SpanID() {
+			t.Errorf("expected POST span %d to be a child of the create span", i)
+		}
+	}
+
+	if creates[0].Status.Code != codes.Unset {
+		t.Errorf("expected first create to succeed, got %v", creates[0].Status)
+	}
+	if creates[1].Status.Code != codes.Error || creates[1].Status.Description != "Error creating Resume" {
+		t.Errorf("expected second create to fail, got %v", creates[1].Status)
+	}
+	if posts[1].Status.Code != codes.Error {
+		t.Errorf("expected failed POST to be recorded, got %v", posts[1].Status)
+	}
+}
+
+func TestStartTracingDisabled(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+
+	before := otel.GetTracerProvider()
+	shutdown, err := StartTracing(context.Background(), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if otel.GetTracerProvider() != before {
+		t.Error("expected tracer provider to be left alone")
+	}
+}
@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// transportConfig holds the TLS and proxy settings of the provider.
+type transportConfig struct {
+	// CACertPEM is trusted in addition to the system certificate pool.
+	CACertPEM          string
+	ClientCertPEM      string
+	ClientKeyPEM       string
+	InsecureSkipVerify bool
+	// ProxyURL overrides the HTTP_PROXY and HTTPS_PROXY environment variables.
+	ProxyURL string
+	// UnixSockets maps host names to the Unix domain sockets dialed instead,
+	// see unixSocketHost.
+	UnixSockets map[string]string
+}
+
+// newTransport returns a transport based on http.DefaultTransport with cfg
+// applied.
+func newTransport(cfg transportConfig) (*http.Transport, error) {
+	//nolint:forcetypeassert
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	if cfg.CACertPEM != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(cfg.CACertPEM)) {
+			return nil, fmt.Errorf("no valid PEM encoded certificate found in CA certificate")
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCertPEM != "" || cfg.ClientKeyPEM != "" {
+		cert, err := tls.X509KeyPair([]byte(cfg.ClientCertPEM), []byte(cfg.ClientKeyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		if proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q: scheme and host are required", cfg.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if len(cfg.UnixSockets) > 0 {
+		dialUnix(transport, cfg.UnixSockets)
+	}
+
+	return transport, nil
+}
+
+// dialUnix makes transport dial the Unix domain socket of a host in sockets
+// instead of connecting through TCP or a proxy.
+func dialUnix(transport *http.Transport, sockets map[string]string) {
+	dialer := &net.Dialer{}
+	dial := transport.DialContext
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(addr)
+		if socket, ok := sockets[host]; ok && err == nil {
+			return dialer.DialContext(ctx, "unix", socket)
+		}
+		return dial(ctx, network, addr)
+	}
+
+	proxy := transport.Proxy
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		if _, ok := sockets[req.URL.Hostname()]; ok || proxy == nil {
+			return nil, nil
+		}
+		return proxy(req)
+	}
+}
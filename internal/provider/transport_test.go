@@ -0,0 +1,281 @@
+package provider
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCertificate is a certificate with its private key, both PEM encoded.
+type testCertificate struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM string
+	keyPEM  string
+}
+
+// newTestCertificate issues a certificate for commonName, signed by parent
+// or self-signed if parent is nil.
+func newTestCertificate(t *testing.T, commonName string, parent *testCertificate) *testCertificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCertificate{
+		cert:    cert,
+		key:     key,
+		certPEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		keyPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+	}
+}
+
+func serverCertPEM(server *httptest.Server) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+}
+
+func get(t *testing.T, cfg transportConfig, url string) error {
+	t.Helper()
+
+	transport, err := newTransport(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := newClient(url, "foo", withHTTPClient(&http.Client{Transport: transport}), withRetry(0, 0))
+	resp, err := c.Get(context.Background(), "/info")
+	if err == nil {
+		resp.Body.Close()
+	}
+	return err
+}
+
+func TestTransportTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	if err := get(t, transportConfig{}, server.URL); err == nil || !strings.Contains(err.Error(), "certificate") {
+		t.Errorf("expected certificate error with the system pool only, got %v", err)
+	}
+	if err := get(t, transportConfig{CACertPEM: serverCertPEM(server)}, server.URL); err != nil {
+		t.Errorf("expected success with the CA certificate, got %v", err)
+	}
+	if err := get(t, transportConfig{InsecureSkipVerify: true}, server.URL); err != nil {
+		t.Errorf("expected success without verification, got %v", err)
+	}
+}
+
+func TestTransportMutualTLS(t *testing.T) {
+	clientCA := newTestCertificate(t, "Client CA", nil)
+	clientCert := newTestCertificate(t, "terraform", clientCA)
+	otherCert := newTestCertificate(t, "someone else", nil)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(clientCA.cert)
+
+	var commonName string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		commonName = r.TLS.PeerCertificates[0].Subject.CommonName
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	server.TLS = &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  pool,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	cfg := transportConfig{
+		CACertPEM:     serverCertPEM(server),
+		ClientCertPEM: clientCert.certPEM,
+		ClientKeyPEM:  clientCert.keyPEM,
+	}
+	if err := get(t, cfg, server.URL); err != nil {
+		t.Fatalf("expected success with client certificate, got %v", err)
+	}
+	if commonName != "terraform" {
+		t.Errorf("expected client certificate for terraform, got %q", commonName)
+	}
+
+	if err := get(t, transportConfig{CACertPEM: serverCertPEM(server)}, server.URL); err == nil {
+		t.Error("expected error without client certificate")
+	}
+
+	cfg.ClientCertPEM, cfg.ClientKeyPEM = otherCert.certPEM, otherCert.keyPEM
+	if err := get(t, cfg, server.URL); err == nil {
+		t.Error("expected error with untrusted client certificate")
+	}
+}
+
+func TestTransportProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer proxy.Close()
+
+	if err := get(t, transportConfig{ProxyURL: proxy.URL}, "http://resume.invalid"); err != nil {
+		t.Fatal(err)
+	}
+	if proxied != "http://resume.invalid/info" {
+		t.Errorf("expected request to be proxied, got %q", proxied)
+	}
+}
+
+// newUnixServer starts a server for handler listening on a Unix domain
+// socket and returns the path of the socket.
+func newUnixServer(t *testing.T, handler http.Handler) string {
+	t.Helper()
+
+	socket := filepath.Join(t.TempDir(), "api.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewUnstartedServer(handler)
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+
+	return socket
+}
+
+func TestTransportUnixSocket(t *testing.T) {
+	var requested string
+	socket := newUnixServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path
+		_, _ = w.Write([]byte(`{}`))
+	}))
+
+	baseURL, _, err := unixBaseURL("unix://" + socket + "/api/v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Requests to the socket bypass the proxy.
+	cfg := transportConfig{
+		ProxyURL:    "http://proxy.invalid",
+		UnixSockets: map[string]string{unixSocketHost(socket): socket},
+	}
+	if err := get(t, cfg, baseURL); err != nil {
+		t.Fatal(err)
+	}
+	if requested != "/api/v1/info" {
+		t.Errorf("expected request to /api/v1/info, got %q", requested)
+	}
+}
+
+func TestParseUnixEndpoint(t *testing.T) {
+	dir := t.TempDir()
+	socket := filepath.Join(dir, "api.sock")
+	if err := os.WriteFile(socket, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		endpoint         string
+		socket, basePath string
+		err              bool
+	}{
+		"socket":          {endpoint: "unix://" + socket, socket: socket},
+		"base path":       {endpoint: "unix://" + socket + "/api/v1", socket: socket, basePath: "/api/v1"},
+		"trailing slash":  {endpoint: "unix://" + socket + "/api/", socket: socket, basePath: "/api"},
+		"missing socket":  {endpoint: "unix://" + dir + "/missing.sock", socket: dir + "/missing.sock"},
+		"host":            {endpoint: "unix://localhost" + socket, err: true},
+		"relative path":   {endpoint: "unix:api.sock", err: true},
+		"query":           {endpoint: "unix://" + socket + "?timeout=1", err: true},
+		"directory":       {endpoint: "unix://" + dir, socket: dir},
+		"root only":       {endpoint: "unix:///", err: true},
+		"invalid escapes": {endpoint: "unix:///%zz", err: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			socket, basePath, err := parseUnixEndpoint(test.endpoint)
+			if test.err {
+				if err == nil {
+					t.Errorf("expected error, got socket %q", socket)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if socket != test.socket || basePath != test.basePath {
+				t.Errorf("expected socket %q and base path %q, got %q and %q",
+					test.socket, test.basePath, socket, basePath)
+			}
+		})
+	}
+}
+
+func TestTransportInvalid(t *testing.T) {
+	cert := newTestCertificate(t, "terraform", nil)
+
+	tests := map[string]transportConfig{
+		"ca certificate":       {CACertPEM: "foo"},
+		"client key":           {ClientCertPEM: cert.certPEM, ClientKeyPEM: "foo"},
+		"mismatched key":       {ClientCertPEM: cert.certPEM, ClientKeyPEM: newTestCertificate(t, "other", nil).keyPEM},
+		"proxy url":            {ProxyURL: "://proxy"},
+		"proxy url has scheme": {ProxyURL: "proxy:3128"},
+	}
+
+	for name, cfg := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := newTransport(cfg); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+)
+
+// isUnixEndpoint reports whether endpoint refers to a Unix domain socket.
+func isUnixEndpoint(endpoint string) bool {
+	return strings.HasPrefix(endpoint, "unix://")
+}
+
+// parseUnixEndpoint splits an endpoint like unix:///run/resume.sock/api into
+// the path of the socket and the base path of the API. The socket is the
+// longest existing file along the path, if there is none yet the whole path
+// is taken as the socket.
+func parseUnixEndpoint(endpoint string) (socket, basePath string, err error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", "", err
+	}
+	if u.Host != "" || !path.IsAbs(u.Path) || path.Clean(u.Path) == "/" || u.RawQuery != "" || u.Fragment != "" {
+		return "", "", fmt.Errorf("%q must be of the form unix:///path/to/socket[/base/path]", endpoint)
+	}
+
+	p := path.Clean(u.Path)
+	for prefix := p; prefix != "/"; prefix = path.Dir(prefix) {
+		if info, err := os.Stat(prefix); err == nil && !info.IsDir() {
+			return prefix, strings.TrimPrefix(p, prefix), nil
+		}
+	}
+	return p, "", nil
+}
+
+// unixBaseURL returns the base URL requests to endpoint are sent to, and the
+// socket which the transport dials for its host, see unixSocketHost.
+func unixBaseURL(endpoint string) (baseURL, socket string, err error) {
+	socket, basePath, err := parseUnixEndpoint(endpoint)
+	if err != nil {
+		return "", "", err
+	}
+	return "http://" + unixSocketHost(socket) + basePath, socket, nil
+}
+
+// unixSocketHost returns the host name standing in for socket in request
+// URLs.
+func unixSocketHost(socket string) string {
+	sum := sha256.Sum256([]byte(socket))
+	return "unix-" + hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,204 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"net"
+	"net/url"
+	"strings"
+)
+
+var (
+	_ basetypes.StringTypable                    = URLType{}
+	_ basetypes.StringValuableWithSemanticEquals = URL{}
+	_ validator.String                           = urlValidator{}
+)
+
+// URLType is a string holding an absolute HTTP(S) URL. Its values are equal
+// if they only differ in the case of the scheme and host, default ports and
+// trailing slashes.
+type URLType struct {
+	basetypes.StringType
+}
+
+func (t URLType) String() string {
+	return "URLType"
+}
+
+func (t URLType) Equal(o attr.Type) bool {
+	other, ok := o.(URLType)
+	return ok && t.StringType.Equal(other.StringType)
+}
+
+func (t URLType) ValueType(ctx context.Context) attr.Value {
+	return URL{}
+}
+
+func (t URLType) ValueFromString(
+	ctx context.Context, in basetypes.StringValue,
+) (basetypes.StringValuable, diag.Diagnostics) {
+	return URL{StringValue: in}, nil
+}
+
+func (t URLType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	value, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := value.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", value)
+	}
+	return URL{StringValue: stringValue}, nil
+}
+
+// URL is a value of URLType.
+type URL struct {
+	basetypes.StringValue
+}
+
+func NewURLNull() URL {
+	return URL{StringValue: basetypes.NewStringNull()}
+}
+
+func NewURLValue(value string) URL {
+	return URL{StringValue: basetypes.NewStringValue(value)}
+}
+
+// NewURLPointerValue returns a null URL if value is nil.
+func NewURLPointerValue(value *string) URL {
+	return URL{StringValue: basetypes.NewStringPointerValue(value)}
+}
+
+func (v URL) Type(ctx context.Context) attr.Type {
+	return URLType{}
+}
+
+func (v URL) Equal(o attr.Value) bool {
+	other, ok := o.(URL)
+	return ok && v.StringValue.Equal(other.StringValue)
+}
+
+// StringSemanticEquals reports whether v and the URL in o are the same once
+// normalized, see normalizeURL.
+func (v URL) StringSemanticEquals(ctx context.Context, o basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	other, ok := o.(URL)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T, got %T. Please report this to the provider developer.", v, o),
+		)
+		return false, diags
+	}
+
+	if v.ValueString() == other.ValueString() {
+		return true, diags
+	}
+	a, errA := normalizeURL(v.ValueString())
+	b, errB := normalizeURL(other.ValueString())
+	return errA == nil && errB == nil && a == b, diags
+}
+
+// parseURL parses value as an absolute HTTP(S) URL.
+func parseURL(value string) (*url.URL, error) {
+	u, err := url.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	if scheme := strings.ToLower(u.Scheme); scheme != "http" && scheme != "https" {
+		return nil, fmt.Errorf("the scheme must be http or https")
+	}
+	if u.Host == "" || u.Hostname() == "" {
+		return nil, fmt.Errorf("the host is missing")
+	}
+	return u, nil
+}
+
+// normalizeURL lower cases the scheme and host of value and removes the
+// default port of the scheme and trailing slashes of the path.
+func normalizeURL(value string) (string, error) {
+	u, err := parseURL(value)
+	if err != nil {
+		return "", err
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	host, port := strings.ToLower(u.Hostname()), u.Port()
+	if u.Scheme == "http" && port == "80" || u.Scheme == "https" && port == "443" {
+		port = ""
+	}
+	u.Host = host
+	if port != "" {
+		u.Host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		u.Host = "[" + host + "]"
+	}
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = strings.TrimRight(u.RawPath, "/")
+	return u.String(), nil
+}
+
+// urlDomains restricts the domains of URLs, a domain includes its
+// subdomains.
+type urlDomains struct {
+	// allowed is empty if every domain which is not denied is allowed.
+	allowed []string
+	denied  []string
+}
+
+// check returns an error if the host of u is not allowed.
+func (d urlDomains) check(u *url.URL) error {
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	for _, domain := range d.denied {
+		if matchDomain(host, domain) {
+			return fmt.Errorf("the domain %s is denied", domain)
+		}
+	}
+	if len(d.allowed) == 0 {
+		return nil
+	}
+	for _, domain := range d.allowed {
+		if matchDomain(host, domain) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not in the allowed domains %s", host, strings.Join(d.allowed, ", "))
+}
+
+func matchDomain(host, domain string) bool {
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// urlValidator validates absolute HTTP(S) URLs, the domains are validated
+// while planning.
+type urlValidator struct{}
+
+func (v urlValidator) Description(ctx context.Context) string {
+	return "value must be an absolute http or https URL"
+}
+
+func (v urlValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v urlValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() || req.ConfigValue.ValueString() == "" {
+		return
+	}
+
+	if _, err := parseURL(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid URL",
+			fmt.Sprintf("%q is not an absolute http or https URL: %v.", req.ConfigValue.ValueString(), err),
+		)
+	}
+}
@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"testing"
+)
+
+func TestURLSemanticEquals(t *testing.T) {
+	cases := []struct {
+		a, b  string
+		equal bool
+	}{
+		{a: "https://example.com", b: "https://example.com", equal: true},
+		{a: "https://Example.com/", b: "https://example.com", equal: true},
+		{a: "HTTPS://EXAMPLE.COM", b: "https://example.com/", equal: true},
+		{a: "https://example.com:443/about/", b: "https://example.com/about", equal: true},
+		{a: "http://example.com:80", b: "http://example.com", equal: true},
+		{a: "http://[::1]:80/", b: "http://[::1]", equal: true},
+		{a: "https://example.com/?page=1", b: "https://example.com?page=1", equal: true},
+		{a: "https://example.com/About", b: "https://example.com/about"},
+		{a: "https://example.com:80", b: "https://example.com"},
+		{a: "http://example.com", b: "https://example.com"},
+		{a: "https://example.com", b: "https://www.example.com"},
+		{a: "https://example.com?page=1", b: "https://example.com?page=2"},
+		{a: "https://example.com", b: ""},
+	}
+
+	ctx := context.Background()
+	for _, c := range cases {
+		equal, diags := NewURLValue(c.a).StringSemanticEquals(ctx, NewURLValue(c.b))
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		if equal != c.equal {
+			t.Errorf("expected %q and %q to be equal %t, got %t", c.a, c.b, c.equal, equal)
+		}
+	}
+}
+
+func TestURLValidator(t *testing.T) {
+	cases := map[string]bool{
+		"https://example.com":        true,
+		"http://example.com:8080/cv": true,
+		"":                           true,
+		"example.com":                false,
+		"/about":                     false,
+		"ftp://example.com":          false,
+		"mailto:jane@example.com":    false,
+		"https://":                   false,
+		"https://exa mple.com":       false,
+	}
+
+	for value, valid := range cases {
+		req := validator.StringRequest{Path: path.Root("website"), ConfigValue: types.StringValue(value)}
+		var resp validator.StringResponse
+		urlValidator{}.ValidateString(context.Background(), req, &resp)
+
+		if resp.Diagnostics.HasError() == valid {
+			t.Errorf("expected %q to be valid %t, got %v", value, valid, resp.Diagnostics)
+		}
+	}
+}
+
+func TestURLDomains(t *testing.T) {
+	cases := []struct {
+		url     string
+		domains urlDomains
+		allowed bool
+	}{
+		{url: "https://example.com", allowed: true},
+		{url: "https://example.com", domains: urlDomains{allowed: []string{"example.com"}}, allowed: true},
+		{url: "https://www.Example.com.", domains: urlDomains{allowed: []string{"example.com"}}, allowed: true},
+		{url: "https://notexample.com", domains: urlDomains{allowed: []string{"example.com"}}},
+		{url: "https://example.org", domains: urlDomains{allowed: []string{"example.com"}}},
+		{url: "https://example.com", domains: urlDomains{denied: []string{"example.com"}}},
+		{url: "https://cv.example.com", domains: urlDomains{denied: []string{"example.com"}}},
+		{url: "https://example.org", domains: urlDomains{denied: []string{"example.com"}}, allowed: true},
+		{
+			url:     "https://ads.example.com",
+			domains: urlDomains{allowed: []string{"example.com"}, denied: []string{"ads.example.com"}},
+		},
+	}
+
+	for _, c := range cases {
+		u, err := parseURL(c.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.domains.check(u); (err == nil) != c.allowed {
+			t.Errorf("expected %q to be allowed %t by %+v, got %v", c.url, c.allowed, c.domains, err)
+		}
+	}
+}
+
+func TestResumeResourceWebsite(t *testing.T) {
+	api := newFakeAPI(t)
+	config := api.config()
+	config.AllowedURLDomains = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("Example.com")})
+	p := api.provider(config)
+
+	resume := testResumePlan("Michael G Scott")
+	resume.Id = types.StringNull()
+	resume.PhoneNumberE164 = types.StringNull()
+
+	resume.Website = NewURLValue("https://dundermifflin.com")
+	_, diags := p.planResume(nil, &resume)
+	if errs := diags.Errors(); len(errs) != 1 || errs[0].Summary() != "URL Domain Not Allowed" {
+		t.Fatalf("expected an URL Domain Not Allowed error, got %v", diags)
+	}
+	if withPath, ok := diags.Errors()[0].(diag.DiagnosticWithPath); !ok || !withPath.Path().Equal(path.Root("website")) {
+		t.Errorf("expected error at website, got %v", diags.Errors()[0])
+	}
+
+	resume.Website = NewURLValue("https://cv.example.com/")
+	plan, diags := p.planResume(nil, &resume)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	created, private, diags := p.applyResume(nil, &plan, nil)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	// The API normalizing the URL is no change.
+	api.change(1, "website", "https://CV.example.com:443")
+	refreshed, _, diags := p.readResume(created, private)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if refreshed.Website.ValueString() != "https://cv.example.com/" {
+		t.Errorf("expected the configured URL to be kept, got %s", refreshed.Website)
+	}
+
+	api.change(1, "website", "https://cv.example.com/about")
+	refreshed, _, diags = p.readResume(created, private)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if refreshed.Website.ValueString() != "https://cv.example.com/about" {
+		t.Errorf("expected the changed URL, got %s", refreshed.Website)
+	}
+}
+
+func TestProviderURLDomainsInvalid(t *testing.T) {
+	api := newFakeAPI(t)
+	config := api.config()
+	config.DeniedURLDomains = types.ListValueMust(types.StringType, []attr.Value{
+		types.StringValue("example.com"),
+		types.StringValue("https://example.org"),
+	})
+
+	_, diags := newTestProvider(t, config)
+	errs := diags.Errors()
+	if len(errs) != 1 || errs[0].Summary() != "Invalid URL Domain" {
+		t.Fatalf("expected an Invalid URL Domain error, got %v", diags)
+	}
+	expected := path.Root("denied_url_domains").AtListIndex(1)
+	if withPath, ok := errs[0].(diag.DiagnosticWithPath); !ok || !withPath.Path().Equal(expected) {
+		t.Errorf("expected error at %s, got %v", expected, errs[0])
+	}
+}
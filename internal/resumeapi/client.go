@@ -0,0 +1,92 @@
+// Package resumeapi is a typed client for the Resume API.
+//
+// Every endpoint is described by an operation, which is checked against the
+// OpenAPI document of the API in openapi.json. Adding an endpoint means
+// adding it to the document, declaring its operation and writing a method
+// calling it.
+//
+// openapi.json is written by hand, it is not exported from the API and has
+// not been validated against any revision of it, including the
+// resume-api:v14 image of docker-compose.yaml. The idempotency_key filter,
+// If-Match preconditions, deleted_at and 410 responses, the structured
+// address, emails, profiles and the versions 1.1.0 and 1.2.0 are only
+// implemented by the fake API of the provider tests. Replace the document
+// with the one exported by the API (e.g. through rswag) once it is
+// available.
+package resumeapi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// Doer calls the Resume API. It sends the body of req as JSON, checks that
+// the response has the status of req and decodes its JSON body into out,
+// which may be nil. The response body is always drained and closed.
+// Failures are returned as diagnostics summarized by req.Summary.
+type Doer interface {
+	DoJSON(ctx context.Context, req Request, out interface{}) (Response, diag.Diagnostics)
+}
+
+// Request is a call of an operation of the Resume API.
+type Request struct {
+	// Summary is the summary of the diagnostics if the call fails.
+	Summary string
+	Method  string
+	// Path is relative to the API endpoint.
+	Path   string
+	Header http.Header
+	// Body is sent as JSON unless it is nil.
+	Body interface{}
+	// Status is the status code of a successful response.
+	Status int
+	// AttributePaths maps the fields of validation errors returned by the
+	// API to the attributes the diagnostics are attached to. Errors of
+	// other fields are not attached to an attribute.
+	AttributePaths map[string]path.Path
+}
+
+// Response is the status and header of the response to a Request.
+// StatusCode is zero if no response was received.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+}
+
+// Client is a typed client for the Resume API.
+type Client struct {
+	doer           Doer
+	attributePaths map[string]path.Path
+}
+
+func New(doer Doer) *Client {
+	return &Client{doer: doer}
+}
+
+// WithAttributePaths returns a copy of c which attaches validation errors to
+// attributes through paths, see Request.AttributePaths.
+func (c *Client) WithAttributePaths(paths map[string]path.Path) *Client {
+	clone := *c
+	clone.attributePaths = paths
+	return &clone
+}
+
+// call sends in as JSON to the endpoint of op and decodes the response into
+// out. Either may be nil.
+func (c *Client) call(
+	ctx context.Context, summary string, op operation, path string, header http.Header, in, out interface{},
+) (Response, diag.Diagnostics) {
+	return c.doer.DoJSON(ctx, Request{
+		Summary: summary,
+		Method:  op.Method,
+		Path:    path,
+		Header:  header,
+		Body:    in,
+		Status:  op.Status,
+
+		AttributePaths: c.attributePaths,
+	}, out)
+}
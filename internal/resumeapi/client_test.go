@@ -0,0 +1,259 @@
+package resumeapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// testDoer sends requests to baseURL like the provider does.
+type testDoer struct {
+	baseURL string
+}
+
+func (d testDoer) DoJSON(ctx context.Context, r Request, out interface{}) (Response, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var body io.Reader
+	if r.Body != nil {
+		payload, err := json.Marshal(r.Body)
+		if err != nil {
+			diags.AddError(r.Summary, err.Error())
+			return Response{}, diags
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.Method, d.baseURL+r.Path, body)
+	if err != nil {
+		diags.AddError(r.Summary, err.Error())
+		return Response{}, diags
+	}
+	for key, values := range r.Header {
+		req.Header[key] = values
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		diags.AddError(r.Summary, err.Error())
+		return Response{}, diags
+	}
+	defer resp.Body.Close()
+
+	result := Response{StatusCode: resp.StatusCode, Header: resp.Header}
+	if resp.StatusCode != r.Status {
+		diags.AddError(r.Summary, fmt.Sprintf("%s %s returned %d", r.Method, r.Path, resp.StatusCode))
+		return result, diags
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			diags.AddError(r.Summary, err.Error())
+		}
+	}
+	return result, diags
+}
+
+type testRequest struct {
+	method         string
+	uri            string
+	idempotencyKey string
+	ifNoneMatch    string
+	ifMatch        string
+	body           string
+}
+
+func newTestClient(t *testing.T, status int, response string) (*Client, *[]testRequest) {
+	t.Helper()
+
+	var requests []testRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, testRequest{
+			method:         r.Method,
+			uri:            r.RequestURI,
+			idempotencyKey: r.Header.Get("Idempotency-Key"),
+			ifNoneMatch:    r.Header.Get("If-None-Match"),
+			ifMatch:        r.Header.Get("If-Match"),
+			body:           string(body),
+		})
+		w.Header().Set("ETag", `"v2"`)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+
+	return New(testDoer{baseURL: server.URL}), &requests
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	website, city, country, empty := "https://michaelthesco.tt", "Scranton", "US", ""
+	resume := Resume{Name: "Michael G Scott", Website: &website}
+	resumeJSON := `{"id":1,"name":"Michael G Scott",` +
+		`"address":{"street_lines":["1725 Slough Avenue"],"city":"Scranton","region":"","country_code":"US"},` +
+		`"phone_number":null,"website":"https://michaelthesco.tt"}`
+	address := Address{StreetLines: []string{"1725 Slough Avenue"}, City: &city, Region: &empty, CountryCode: &country}
+	created := Resume{Id: 1, Name: "Michael G Scott", Address: &address, Website: &website}
+	versioned := created
+	versioned.ETag = `"v2"`
+
+	tests := map[string]struct {
+		status   int
+		response string
+		call     func(c *Client) (interface{}, diag.Diagnostics)
+		expected interface{}
+		request  testRequest
+	}{
+		"GetInfo": {
+			status:   http.StatusOK,
+			response: `{"name":"Resume API","version":"1.0.0","environment":"development"}`,
+			call: func(c *Client) (interface{}, diag.Diagnostics) {
+				info, _, diags := c.GetInfo(ctx)
+				return info, diags
+			},
+			expected: &Info{Name: "Resume API", Version: "1.0.0", Environment: "development"},
+			request:  testRequest{method: http.MethodGet, uri: "/info"},
+		},
+		"ListResumes": {
+			status:   http.StatusOK,
+			response: "[" + resumeJSON + "]",
+			call: func(c *Client) (interface{}, diag.Diagnostics) {
+				var resumes []Resume
+				var diags diag.Diagnostics
+				c.ListResumes(ctx, ListResumesOptions{IdempotencyKey: "a b"})(func(resume Resume, d diag.Diagnostics) bool {
+					resumes, diags = append(resumes, resume), d
+					return !d.HasError()
+				})
+				return resumes, diags
+			},
+			expected: []Resume{created},
+			request:  testRequest{method: http.MethodGet, uri: "/resumes?idempotency_key=a+b"},
+		},
+		"CreateResume": {
+			status:   http.StatusCreated,
+			response: resumeJSON,
+			call: func(c *Client) (interface{}, diag.Diagnostics) {
+				created, _, diags := c.CreateResume(ctx, resume, "key")
+				return created, diags
+			},
+			expected: &versioned,
+			request: testRequest{
+				method:         http.MethodPost,
+				uri:            "/resumes",
+				idempotencyKey: "key",
+				body:           `{"name":"Michael G Scott","website":"https://michaelthesco.tt"}`,
+			},
+		},
+		"GetResume": {
+			status:   http.StatusOK,
+			response: resumeJSON,
+			call: func(c *Client) (interface{}, diag.Diagnostics) {
+				read, _, diags := c.GetResume(ctx, "1", GetResumeOptions{IfNoneMatch: `"v1"`})
+				return read, diags
+			},
+			expected: &versioned,
+			request:  testRequest{method: http.MethodGet, uri: "/resumes/1", ifNoneMatch: `"v1"`},
+		},
+		"UpdateResume": {
+			status:   http.StatusOK,
+			response: resumeJSON,
+			call: func(c *Client) (interface{}, diag.Diagnostics) {
+				patch := ResumePatch{
+					Address:     Value(Address{StreetLines: []string{"1725 Slough Avenue"}, City: &city}),
+					PhoneNumber: Null[string](),
+					Website:     Value(website),
+				}
+				updated, _, diags := c.UpdateResume(ctx, "1", patch, WriteOptions{IfMatch: `"v1"`})
+				return updated, diags
+			},
+			expected: &versioned,
+			request: testRequest{
+				method:  http.MethodPatch,
+				uri:     "/resumes/1",
+				ifMatch: `"v1"`,
+				body: `{"address":{"street_lines":["1725 Slough Avenue"],"city":"Scranton"},` +
+					`"phone_number":null,"website":"https://michaelthesco.tt"}`,
+			},
+		},
+		"DeleteResume": {
+			status: http.StatusNoContent,
+			call: func(c *Client) (interface{}, diag.Diagnostics) {
+				_, diags := c.DeleteResume(ctx, "1", WriteOptions{IfMatch: `"v1"`})
+				return nil, diags
+			},
+			expected: nil,
+			request:  testRequest{method: http.MethodDelete, uri: "/resumes/1", ifMatch: `"v1"`},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, requests := newTestClient(t, test.status, test.response)
+
+			actual, diags := test.call(c)
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected %#v, got %#v", test.expected, actual)
+			}
+			if len(*requests) != 1 || (*requests)[0] != test.request {
+				t.Errorf("expected request %+v, got %+v", test.request, *requests)
+			}
+		})
+	}
+}
+
+func TestClientUnexpectedStatus(t *testing.T) {
+	c, _ := newTestClient(t, http.StatusOK, `{}`)
+
+	_, status, diags := c.CreateResume(context.Background(), Resume{Name: "foo"}, "")
+	if status != http.StatusOK || diags.ErrorsCount() != 1 || diags[0].Summary() != "Error creating Resume" {
+		t.Errorf("expected an Error creating Resume with status 200, got %d: %v", status, diags)
+	}
+}
+
+// doerFunc is a Doer calling itself.
+type doerFunc func(ctx context.Context, r Request, out interface{}) (Response, diag.Diagnostics)
+
+func (f doerFunc) DoJSON(ctx context.Context, r Request, out interface{}) (Response, diag.Diagnostics) {
+	return f(ctx, r, out)
+}
+
+func TestClientAttributePaths(t *testing.T) {
+	var requests []Request
+	doer := doerFunc(func(ctx context.Context, r Request, out interface{}) (Response, diag.Diagnostics) {
+		requests = append(requests, r)
+		return Response{StatusCode: r.Status}, nil
+	})
+	paths := map[string]path.Path{"name": path.Root("name")}
+
+	c := New(doer)
+	_, _, _ = c.WithAttributePaths(paths).GetResume(context.Background(), "1", GetResumeOptions{})
+	_, _, _ = c.GetResume(context.Background(), "1", GetResumeOptions{})
+
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+	if !reflect.DeepEqual(requests[0].AttributePaths, paths) {
+		t.Errorf("expected the attribute paths on the request, got %v", requests[0].AttributePaths)
+	}
+	if requests[1].AttributePaths != nil {
+		t.Errorf("expected the original client to be unchanged, got %v", requests[1].AttributePaths)
+	}
+}
+
+func TestOperationPath(t *testing.T) {
+	if path := getResume.path("id", "a/b"); path != "/resumes/a%2Fb" {
+		t.Errorf("expected escaped path, got %s", path)
+	}
+}
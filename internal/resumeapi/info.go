@@ -0,0 +1,23 @@
+package resumeapi
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// Info describes the API instance.
+type Info struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Environment string `json:"environment"`
+}
+
+func (c *Client) GetInfo(ctx context.Context) (*Info, int, diag.Diagnostics) {
+	var info Info
+	resp, diags := c.call(ctx, "Error reading Resume API info", getInfo, getInfo.path(), nil, nil, &info)
+	if diags.HasError() {
+		return nil, resp.StatusCode, diags
+	}
+	return &info, resp.StatusCode, diags
+}
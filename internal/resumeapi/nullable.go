@@ -0,0 +1,41 @@
+package resumeapi
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Nullable is a JSON value which is either a T or null. A *Nullable field
+// with omitempty tells apart leaving a field out of a request, nil, from
+// clearing it, Null.
+type Nullable[T any] struct {
+	Value T
+	// Valid is false if the value is null.
+	Valid bool
+}
+
+// Null returns a null value.
+func Null[T any]() *Nullable[T] {
+	return &Nullable[T]{}
+}
+
+// Value returns a value which is not null.
+func Value[T any](value T) *Nullable[T] {
+	return &Nullable[T]{Value: value, Valid: true}
+}
+
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Value)
+}
+
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*n = Nullable[T]{}
+		return nil
+	}
+	n.Valid = true
+	return json.Unmarshal(data, &n.Value)
+}
@@ -0,0 +1,147 @@
+package resumeapi
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type openAPIDocument struct {
+	Paths map[string]map[string]json.RawMessage `json:"paths"`
+	// Components only covers the schemas.
+	Components struct {
+		Schemas map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"schemas"`
+	} `json:"components"`
+}
+
+type openAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	Responses   map[string]json.RawMessage `json:"responses"`
+}
+
+func loadOpenAPIDocument(t *testing.T) openAPIDocument {
+	t.Helper()
+
+	raw, err := os.ReadFile("openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc openAPIDocument
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+// documentedOperations returns every operation of doc keyed by operationId.
+func documentedOperations(t *testing.T, doc openAPIDocument) map[string]operation {
+	t.Helper()
+
+	ops := map[string]operation{}
+	for path, item := range doc.Paths {
+		for method, raw := range item {
+			if method == "parameters" {
+				continue
+			}
+
+			var op openAPIOperation
+			if err := json.Unmarshal(raw, &op); err != nil {
+				t.Fatalf("%s %s: %v", method, path, err)
+			}
+
+			status := 0
+			for code := range op.Responses {
+				if c, err := strconv.Atoi(code); err == nil && c >= 200 && c <= 299 {
+					if status != 0 {
+						t.Errorf("%s: expected a single success response", op.OperationID)
+					}
+					status = c
+				}
+			}
+
+			ops[op.OperationID] = operation{
+				ID:     op.OperationID,
+				Method: strings.ToUpper(method),
+				Path:   path,
+				Status: status,
+			}
+		}
+	}
+	return ops
+}
+
+func TestOperationsMatchOpenAPIDocument(t *testing.T) {
+	documented := documentedOperations(t, loadOpenAPIDocument(t))
+
+	implemented := map[string]bool{}
+	for _, op := range operations {
+		implemented[op.ID] = true
+
+		doc, ok := documented[op.ID]
+		if !ok {
+			t.Errorf("%s is not documented in openapi.json", op.ID)
+			continue
+		}
+		if !reflect.DeepEqual(op, doc) {
+			t.Errorf("%s does not match openapi.json:\nexpected %+v\ngot      %+v", op.ID, doc, op)
+		}
+	}
+
+	for id := range documented {
+		if !implemented[id] {
+			t.Errorf("%s is documented in openapi.json but not implemented", id)
+		}
+	}
+}
+
+func TestTypesMatchOpenAPIDocument(t *testing.T) {
+	doc := loadOpenAPIDocument(t)
+
+	types := map[string]interface{}{
+		"Address":     Address{},
+		"Email":       Email{},
+		"Info":        Info{},
+		"Profile":     Profile{},
+		"Resume":      Resume{},
+		"ResumePatch": ResumePatch{},
+	}
+
+	for name, value := range types {
+		schema, ok := doc.Components.Schemas[name]
+		if !ok {
+			t.Errorf("schema %s is not documented in openapi.json", name)
+			continue
+		}
+
+		var expected []string
+		for property := range schema.Properties {
+			expected = append(expected, property)
+		}
+		sort.Strings(expected)
+
+		actual := jsonFields(reflect.TypeOf(value))
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s does not match openapi.json:\nexpected %v\ngot      %v", name, expected, actual)
+		}
+	}
+}
+
+func jsonFields(typ reflect.Type) []string {
+	var fields []string
+	for i := 0; i < typ.NumField(); i++ {
+		tag := typ.Field(i).Tag.Get("json")
+		name, _, _ := strings.Cut(tag, ",")
+		if name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
@@ -0,0 +1,48 @@
+package resumeapi
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// operation describes an endpoint of the API as it is documented in
+// openapi.json.
+type operation struct {
+	// ID is the operationId in openapi.json.
+	ID     string
+	Method string
+	// Path is the path template, i.e. "/resumes/{id}".
+	Path string
+	// Status is the status code of a successful response.
+	Status int
+}
+
+var (
+	getInfo      = operation{ID: "getInfo", Method: http.MethodGet, Path: "/info", Status: http.StatusOK}
+	listResumes  = operation{ID: "listResumes", Method: http.MethodGet, Path: "/resumes", Status: http.StatusOK}
+	createResume = operation{ID: "createResume", Method: http.MethodPost, Path: "/resumes", Status: http.StatusCreated}
+	getResume    = operation{ID: "getResume", Method: http.MethodGet, Path: "/resumes/{id}", Status: http.StatusOK}
+	updateResume = operation{ID: "updateResume", Method: http.MethodPatch, Path: "/resumes/{id}", Status: http.StatusOK}
+	deleteResume = operation{ID: "deleteResume", Method: http.MethodDelete, Path: "/resumes/{id}", Status: http.StatusNoContent}
+)
+
+// operations lists every operation implemented by Client.
+var operations = []operation{
+	getInfo,
+	listResumes,
+	createResume,
+	getResume,
+	updateResume,
+	deleteResume,
+}
+
+// path expands the path template of op with params, given as name and value
+// pairs.
+func (op operation) path(params ...string) string {
+	path := op.Path
+	for i := 0; i+1 < len(params); i += 2 {
+		path = strings.ReplaceAll(path, "{"+params[i]+"}", url.PathEscape(params[i+1]))
+	}
+	return path
+}
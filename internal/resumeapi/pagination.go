@@ -0,0 +1,121 @@
+package resumeapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// Seq2 has the shape of iter.Seq2 from Go 1.23. Call it with a yield function
+// which returns false to stop the iteration early.
+type Seq2[K, V any] func(yield func(K, V) bool)
+
+// paginate iterates over every item of the collection at path, which is the
+// path of op. It follows the next link of the Link header or, if there is
+// none, the X-Next-Cursor header. At most limit items are returned, unless
+// limit is zero.
+//
+// Failures are yielded as diagnostics summarized by summary with the zero
+// value of T and end the iteration.
+func paginate[T any](
+	ctx context.Context, c *Client, summary string, op operation, query url.Values, limit int,
+) Seq2[T, diag.Diagnostics] {
+	return func(yield func(T, diag.Diagnostics) bool) {
+		var zero T
+		count := 0
+
+		for query != nil {
+			if err := ctx.Err(); err != nil {
+				var diags diag.Diagnostics
+				diags.AddError(summary, err.Error())
+				yield(zero, diags)
+				return
+			}
+
+			path := op.path()
+			if encoded := query.Encode(); encoded != "" {
+				path += "?" + encoded
+			}
+
+			var page []T
+			resp, diags := c.call(ctx, summary, op, path, nil, nil, &page)
+			if diags.HasError() {
+				yield(zero, diags)
+				return
+			}
+
+			for _, item := range page {
+				if limit > 0 && count >= limit {
+					return
+				}
+				if !yield(item, nil) {
+					return
+				}
+				count++
+			}
+
+			next := nextPage(resp.Header, query)
+			if len(page) == 0 || (limit > 0 && count >= limit) || reflect.DeepEqual(next, query) {
+				return
+			}
+			query = next
+		}
+	}
+}
+
+// nextPage returns the query of the page after the one requested with query,
+// or nil if it was the last page.
+func nextPage(header http.Header, query url.Values) url.Values {
+	if link, ok := nextLink(header.Values("Link")); ok {
+		next, err := url.Parse(link)
+		if err != nil {
+			return nil
+		}
+		// The next page is always of the same collection, only the query is
+		// taken so links work regardless of the path the API is mounted at.
+		return next.Query()
+	}
+
+	if cursor := header.Get("X-Next-Cursor"); cursor != "" {
+		next := url.Values{}
+		for key, values := range query {
+			next[key] = values
+		}
+		next.Set("cursor", cursor)
+		return next
+	}
+
+	return nil
+}
+
+// nextLink returns the target of rel="next" in the Link headers values, as
+// defined by RFC 8288.
+func nextLink(values []string) (string, bool) {
+	for _, value := range values {
+		for _, link := range strings.Split(value, ",") {
+			parts := strings.Split(link, ";")
+			target := strings.TrimSpace(parts[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+
+			for _, param := range parts[1:] {
+				name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if !strings.EqualFold(name, "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(value, `"`)) {
+					if strings.EqualFold(rel, "next") {
+						return target[1 : len(target)-1], true
+					}
+				}
+			}
+		}
+	}
+
+	return "", false
+}
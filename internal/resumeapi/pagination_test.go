@@ -0,0 +1,229 @@
+package resumeapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const testRecords = 2500
+
+// newPaginatedServer serves testRecords resumes in pages of per_page items.
+// With cursors set, the next page is announced in X-Next-Cursor, otherwise
+// in the Link header.
+func newPaginatedServer(t *testing.T, cursors bool) (*Client, *int32) {
+	t.Helper()
+
+	var requests int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		perPage, err := strconv.Atoi(r.URL.Query().Get("per_page"))
+		if err != nil {
+			perPage = 25
+		}
+
+		start := 0
+		if cursors {
+			if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+				start, _ = strconv.Atoi(cursor)
+			}
+		} else if page, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil {
+			start = (page - 1) * perPage
+		}
+
+		end := start + perPage
+		if end > testRecords {
+			end = testRecords
+		}
+
+		if end < testRecords {
+			if cursors {
+				w.Header().Set("X-Next-Cursor", strconv.Itoa(end))
+			} else {
+				page := end/perPage + 1
+				w.Header().Add("Link", fmt.Sprintf(
+					`<%s/api/resumes?page=1&per_page=%d>; rel="first", <%s/api/resumes?page=%d&per_page=%d>; rel="next"`,
+					server.URL, perPage, server.URL, page, perPage,
+				))
+			}
+		}
+
+		resumes := make([]Resume, 0, perPage)
+		for id := start; id < end; id++ {
+			resumes = append(resumes, Resume{Id: int64(id + 1), Name: fmt.Sprintf("Resume %d", id+1)})
+		}
+		_ = json.NewEncoder(w).Encode(resumes)
+	}))
+	t.Cleanup(server.Close)
+
+	return New(testDoer{baseURL: server.URL}), &requests
+}
+
+func collect(seq Seq2[Resume, diag.Diagnostics]) ([]Resume, diag.Diagnostics) {
+	var resumes []Resume
+	var diags diag.Diagnostics
+	seq(func(resume Resume, d diag.Diagnostics) bool {
+		if d.HasError() {
+			diags = d
+			return false
+		}
+		resumes = append(resumes, resume)
+		return true
+	})
+	return resumes, diags
+}
+
+func TestListResumesPagination(t *testing.T) {
+	tests := map[string]struct {
+		cursors  bool
+		opts     ListResumesOptions
+		expected int
+		requests int32
+	}{
+		"link header": {
+			opts:     ListResumesOptions{PerPage: 100},
+			expected: testRecords,
+			requests: 25,
+		},
+		"link header with uneven pages": {
+			opts:     ListResumesOptions{PerPage: 300},
+			expected: testRecords,
+			requests: 9,
+		},
+		"cursor": {
+			cursors:  true,
+			opts:     ListResumesOptions{PerPage: 100},
+			expected: testRecords,
+			requests: 25,
+		},
+		"default page size": {
+			cursors:  true,
+			expected: testRecords,
+			requests: 100,
+		},
+		"limit": {
+			opts:     ListResumesOptions{PerPage: 100, Limit: 250},
+			expected: 250,
+			requests: 3,
+		},
+		"limit on page boundary": {
+			opts:     ListResumesOptions{PerPage: 100, Limit: 200},
+			expected: 200,
+			requests: 2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, requests := newPaginatedServer(t, test.cursors)
+
+			resumes, diags := collect(c.ListResumes(context.Background(), test.opts))
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+
+			if len(resumes) != test.expected {
+				t.Fatalf("expected %d resumes, got %d", test.expected, len(resumes))
+			}
+			for i, resume := range resumes {
+				if resume.Id != int64(i+1) {
+					t.Fatalf("expected resume %d at index %d, got %d", i+1, i, resume.Id)
+				}
+			}
+			if *requests != test.requests {
+				t.Errorf("expected %d requests, got %d", test.requests, *requests)
+			}
+		})
+	}
+}
+
+func TestListResumesStop(t *testing.T) {
+	c, requests := newPaginatedServer(t, false)
+
+	count := 0
+	c.ListResumes(context.Background(), ListResumesOptions{PerPage: 100})(func(resume Resume, diags diag.Diagnostics) bool {
+		count++
+		return count < 150
+	})
+
+	if count != 150 || *requests != 2 {
+		t.Errorf("expected to stop after 150 resumes and 2 requests, got %d and %d", count, *requests)
+	}
+}
+
+func TestListResumesCanceled(t *testing.T) {
+	c, requests := newPaginatedServer(t, true)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var diags diag.Diagnostics
+	count := 0
+	c.ListResumes(ctx, ListResumesOptions{PerPage: 100})(func(resume Resume, d diag.Diagnostics) bool {
+		if d.HasError() {
+			diags = d
+			return false
+		}
+		count++
+		if count == 100 {
+			cancel()
+		}
+		return true
+	})
+
+	if diags.ErrorsCount() != 1 || diags[0].Detail() != context.Canceled.Error() {
+		t.Errorf("expected context.Canceled, got %v", diags)
+	}
+	if count != 100 || *requests != 1 {
+		t.Errorf("expected to stop after 100 resumes and 1 request, got %d and %d", count, *requests)
+	}
+}
+
+func TestNextLink(t *testing.T) {
+	tests := map[string]struct {
+		values   []string
+		expected string
+		ok       bool
+	}{
+		"none": {},
+		"next": {
+			values:   []string{`<https://api/resumes?page=2>; rel="next"`},
+			expected: "https://api/resumes?page=2",
+			ok:       true,
+		},
+		"multiple links": {
+			values:   []string{`<https://api/resumes?page=1>; rel="prev", <https://api/resumes?page=3>; rel=next`},
+			expected: "https://api/resumes?page=3",
+			ok:       true,
+		},
+		"multiple headers": {
+			values:   []string{`</resumes?page=9>; rel="last"`, `</resumes?page=2>; title="Next"; rel="next"`},
+			expected: "/resumes?page=2",
+			ok:       true,
+		},
+		"multiple rels": {
+			values:   []string{`</resumes?cursor=b>; rel="next last"`},
+			expected: "/resumes?cursor=b",
+			ok:       true,
+		},
+		"last page": {
+			values: []string{`</resumes?page=1>; rel="first"`},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, ok := nextLink(test.values)
+			if actual != test.expected || ok != test.ok {
+				t.Errorf("expected (%q, %t), got (%q, %t)", test.expected, test.ok, actual, ok)
+			}
+		})
+	}
+}
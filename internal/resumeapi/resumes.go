@@ -0,0 +1,169 @@
+package resumeapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// Resume as stored by the API. Optional fields are nil if they are null,
+// which is distinct from an empty string.
+type Resume struct {
+	Id          int64      `json:"id,omitempty"`
+	Name        string     `json:"name"`
+	Address     *Address   `json:"address,omitempty"`
+	PhoneNumber *string    `json:"phone_number,omitempty"`
+	Website     *string    `json:"website,omitempty"`
+	Emails      *[]Email   `json:"emails,omitempty"`
+	Profiles    *[]Profile `json:"profiles,omitempty"`
+
+	// DeletedAt is set once the resume was deleted in the web UI, the API
+	// keeps serving it until it is purged.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+
+	// ETag identifies the version of the resume returned by the API.
+	ETag string `json:"-"`
+}
+
+// Address is a postal address, its optional fields are nil if they are null.
+type Address struct {
+	StreetLines []string `json:"street_lines"`
+	City        *string  `json:"city,omitempty"`
+	Region      *string  `json:"region,omitempty"`
+	PostalCode  *string  `json:"postal_code,omitempty"`
+	// CountryCode is an ISO 3166-1 alpha-2 code.
+	CountryCode *string `json:"country_code,omitempty"`
+}
+
+// Email is an email address of a resume, exactly one of them is primary.
+type Email struct {
+	Address string  `json:"address"`
+	Label   *string `json:"label,omitempty"`
+	Primary bool    `json:"primary"`
+}
+
+// Profile is an online profile like in basics.profiles of JSON Resume.
+type Profile struct {
+	Network  string  `json:"network"`
+	Username *string `json:"username,omitempty"`
+	URL      *string `json:"url,omitempty"`
+}
+
+// GetResumeOptions are the conditions for GetResume.
+type GetResumeOptions struct {
+	// IfNoneMatch is the ETag of a previously read resume. If it did not
+	// change since, the API responds with 304 Not Modified, which fails the
+	// call with that status.
+	IfNoneMatch string
+}
+
+// ListResumesOptions filters the resumes returned by ListResumes.
+type ListResumesOptions struct {
+	// IdempotencyKey only returns the resume created with this key.
+	IdempotencyKey string
+	// PerPage is the number of resumes fetched per request, the API default
+	// is used if zero.
+	PerPage int
+	// Limit caps the number of resumes returned, all are returned if zero.
+	Limit int
+}
+
+// ListResumes iterates over all resumes, fetching them page by page.
+func (c *Client) ListResumes(ctx context.Context, opts ListResumesOptions) Seq2[Resume, diag.Diagnostics] {
+	query := url.Values{}
+	if opts.IdempotencyKey != "" {
+		query.Set("idempotency_key", opts.IdempotencyKey)
+	}
+	if opts.PerPage > 0 {
+		query.Set("per_page", strconv.Itoa(opts.PerPage))
+	}
+
+	return paginate[Resume](ctx, c, "Error listing Resumes", listResumes, query, opts.Limit)
+}
+
+// CreateResume creates resume. The API creates at most one resume per
+// idempotencyKey, which makes it safe to retry.
+//
+// Like every method of Client, it also returns the status code of the
+// response, which is zero if none was received.
+func (c *Client) CreateResume(
+	ctx context.Context, resume Resume, idempotencyKey string,
+) (*Resume, int, diag.Diagnostics) {
+	header := http.Header{}
+	if idempotencyKey != "" {
+		header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	var created Resume
+	resp, diags := c.call(ctx, "Error creating Resume", createResume, createResume.path(), header, resume, &created)
+	if diags.HasError() {
+		return nil, resp.StatusCode, diags
+	}
+	created.ETag = resp.Header.Get("ETag")
+	return &created, resp.StatusCode, diags
+}
+
+func (c *Client) GetResume(ctx context.Context, id string, opts GetResumeOptions) (*Resume, int, diag.Diagnostics) {
+	header := http.Header{}
+	if opts.IfNoneMatch != "" {
+		header.Set("If-None-Match", opts.IfNoneMatch)
+	}
+
+	var resume Resume
+	resp, diags := c.call(ctx, "Error reading Resume", getResume, getResume.path("id", id), header, nil, &resume)
+	if diags.HasError() {
+		return nil, resp.StatusCode, diags
+	}
+	resume.ETag = resp.Header.Get("ETag")
+	return &resume, resp.StatusCode, diags
+}
+
+// WriteOptions are the conditions for UpdateResume and DeleteResume.
+type WriteOptions struct {
+	// IfMatch is the ETag of the resume the change is based on. If the
+	// resume changed since, the API responds with 412 Precondition Failed,
+	// which fails the call with that status.
+	IfMatch string
+}
+
+func (o WriteOptions) header() http.Header {
+	header := http.Header{}
+	if o.IfMatch != "" {
+		header.Set("If-Match", o.IfMatch)
+	}
+	return header
+}
+
+// ResumePatch changes the fields of a resume which are not nil. Optional
+// fields are cleared by setting them to Null.
+type ResumePatch struct {
+	Name        *string              `json:"name,omitempty"`
+	Address     *Nullable[Address]   `json:"address,omitempty"`
+	PhoneNumber *Nullable[string]    `json:"phone_number,omitempty"`
+	Website     *Nullable[string]    `json:"website,omitempty"`
+	Emails      *Nullable[[]Email]   `json:"emails,omitempty"`
+	Profiles    *Nullable[[]Profile] `json:"profiles,omitempty"`
+}
+
+func (c *Client) UpdateResume(
+	ctx context.Context, id string, patch ResumePatch, opts WriteOptions,
+) (*Resume, int, diag.Diagnostics) {
+	var updated Resume
+	resp, diags := c.call(
+		ctx, "Error updating Resume", updateResume, updateResume.path("id", id), opts.header(), patch, &updated,
+	)
+	if diags.HasError() {
+		return nil, resp.StatusCode, diags
+	}
+	updated.ETag = resp.Header.Get("ETag")
+	return &updated, resp.StatusCode, diags
+}
+
+func (c *Client) DeleteResume(ctx context.Context, id string, opts WriteOptions) (int, diag.Diagnostics) {
+	resp, diags := c.call(ctx, "Error deleting Resume", deleteResume, deleteResume.path("id", id), opts.header(), nil, nil)
+	return resp.StatusCode, diags
+}